@@ -1,32 +1,26 @@
 package tammany
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/SlothNinja/game"
 	"github.com/SlothNinja/log"
+	"github.com/SlothNinja/user"
 	"github.com/gin-gonic/gin"
 )
 
-func (g *Game) adminState(c *gin.Context) (string, game.ActionType, error) {
+func (g *Game) adminState(c *gin.Context, cu *user.User) (string, game.ActionType, error) {
 	log.Debugf(msgEnter)
 	defer log.Debugf(msgExit)
 
 	h := struct {
 		Title         string           `form:"title"`
-		Turn          int              `form:"turn"" binding:"min=0"`
+		Turn          int              `form:"turn" binding:"min=0"`
 		Phase         game.Phase       `form:"phase" binding:"min=0"`
 		SubPhase      game.SubPhase    `form:"sub-phase" binding:"min=0"`
 		Round         int              `form:"round" binding:"min=0"`
-		NumPlayers    int              `form:"num-players" binding"min=0,max=5"`
-		Password      string           `form:"password"`
-		CreatorID     int64            `form:"creator-id"`
-		CreatorSID    string           `form:"creator-sid"`
-		CreatorName   string           `form:"creator-name"`
-		UserIDS       []int64          `form:"user-ids"`
-		UserSIDS      []string         `form:"user-sids"`
-		UserNames     []string         `form:"user-names"`
-		UserEmails    []string         `form:"user-emails"`
+		NumPlayers    int              `form:"num-players" binding:"min=0,max=5"`
 		OrderIDS      game.UserIndices `form:"order-ids"`
 		CPUserIndices game.UserIndices `form:"cp-user-indices"`
 		WinnerIDS     game.UserIndices `form:"winner-ids"`
@@ -48,21 +42,36 @@ func (g *Game) adminState(c *gin.Context) (string, game.ActionType, error) {
 	// 	return "", game.None, err
 	// }
 
-	g.UserIDS = h.UserIDS
-	g.Title = h.Title
-	g.Phase = h.Phase
-	g.Round = h.Round
-	g.NumPlayers = h.NumPlayers
-	g.Password = h.Password
-	g.CreatorID = h.CreatorID
+	// The users of the game are not edited by the form, as their values are not noted with the action.
+	v := &adminStateView{
+		UserIDS:       g.UserIDS,
+		Title:         h.Title,
+		Phase:         h.Phase,
+		Round:         h.Round,
+		NumPlayers:    h.NumPlayers,
+		CreatorID:     g.CreatorID,
+		CPUserIndices: g.CPUserIndices,
+		WinnerIDS:     g.WinnerIDS,
+		Status:        h.Status,
+	}
 	if !(len(h.CPUserIndices) == 1 && h.CPUserIndices[0] == -1) {
-		g.CPUserIndices = h.CPUserIndices
+		v.CPUserIndices = h.CPUserIndices
 	}
 	if !(len(h.WinnerIDS) == 1 && h.WinnerIDS[0] == -1) {
-		g.WinnerIDS = h.WinnerIDS
+		v.WinnerIDS = h.WinnerIDS
+	}
+
+	values, err := json.Marshal(v)
+	if err != nil {
+		return "", game.None, err
 	}
-	g.Status = h.Status
-	return "", game.Save, nil
+
+	// The password is changed by the logged edit, yet only the change, not its value, is logged.
+	ed := &AdminEdit{Target: adminStateTarget, Values: values}
+	if password, ok := c.GetPostForm("password"); ok {
+		ed.password = &password
+	}
+	return g.adminFormEdit(cu, c.PostForm("reason"), ed)
 }
 
 type chips struct {
@@ -77,7 +86,7 @@ func newChips() *chips {
 	}
 }
 
-func (g *Game) adminPlayer(c *gin.Context) (string, game.ActionType, error) {
+func (g *Game) adminPlayer(c *gin.Context, cu *user.User) (string, game.ActionType, error) {
 	log.Debugf(msgEnter)
 	defer log.Debugf(msgExit)
 	obj := struct {
@@ -101,25 +110,30 @@ func (g *Game) adminPlayer(c *gin.Context) (string, game.ActionType, error) {
 		return "", game.None, err
 	}
 
-	p2 := g.PlayerByID(obj.IDF)
-
-	for i, n := range g.Nationalities() {
-		p2.Chips[n] = obj.Chips[i]
-		p2.PlayedChips[n] = obj.PlayedChips[i]
+	ns := g.Nationalities()
+	if len(obj.Chips) != len(ns) || len(obj.PlayedChips) != len(ns) {
+		return "", game.None, g.verror(ErrInvalidValue, "Expected %d chip and played chip counts.", len(ns))
 	}
 
-	p2.Score = obj.Score
-	p2.PerformedAction = obj.PerformedAction
-	p2.Candidate = obj.Candidate
-	p2.UsedOffice = obj.UsedOffice
-	p2.PlacedBosses = obj.PlacedBosses
-	p2.PlacedImmigrants = obj.PlacedImmigrants
-	p2.HasBid = obj.HasBid
-
-	return "", game.Save, nil
+	v := &adminPlayerView{
+		Score:            obj.Score,
+		Chips:            make(map[string]int, len(ns)),
+		PlayedChips:      make(map[string]int, len(ns)),
+		PerformedAction:  obj.PerformedAction,
+		Candidate:        obj.Candidate,
+		UsedOffice:       obj.UsedOffice,
+		PlacedBosses:     obj.PlacedBosses,
+		PlacedImmigrants: obj.PlacedImmigrants,
+		HasBid:           obj.HasBid,
+	}
+	for i, n := range ns {
+		v.Chips[n.String()] = obj.Chips[i]
+		v.PlayedChips[n.String()] = obj.PlayedChips[i]
+	}
+	return g.adminEdit(cu, c.PostForm("reason"), adminPlayerTarget, obj.IDF, v)
 }
 
-func (g *Game) adminWard(c *gin.Context) (string, game.ActionType, error) {
+func (g *Game) adminWard(c *gin.Context, cu *user.User) (string, game.ActionType, error) {
 	log.Debugf(msgEnter)
 	defer log.Debugf(msgExit)
 
@@ -143,23 +157,23 @@ func (g *Game) adminWard(c *gin.Context) (string, game.ActionType, error) {
 	// 	return "", game.None, err
 	// }
 
-	w1 := g.wardByID(w2.ID)
-	w1.Immigrants[irish] = w2.Irish
-	w1.Immigrants[german] = w2.German
-	w1.Immigrants[italian] = w2.Italian
-	w1.Immigrants[english] = w2.English
+	if len(w2.Bosses) != len(g.Players()) {
+		return "", game.None, g.verror(ErrInvalidValue, "Expected %d boss counts.", len(g.Players()))
+	}
 
+	v := &adminWardView{
+		Immigrants: nationalsForm(w2.Irish, w2.English, w2.German, w2.Italian),
+		Bosses:     make(map[int]int, len(g.Players())),
+		Resolved:   w2.Resolved,
+		LockedUp:   w2.LockedUp,
+	}
 	for i, p := range g.Players() {
-		w1.Bosses[p.ID()] = w2.Bosses[i]
+		v.Bosses[p.ID()] = w2.Bosses[i]
 	}
-
-	w1.Resolved = w2.Resolved
-	w1.LockedUp = w2.LockedUp
-
-	return "", game.Save, nil
+	return g.adminEdit(cu, c.PostForm("reason"), adminWardTarget, int(w2.ID), v)
 }
 
-func (g *Game) adminCastleGarden(c *gin.Context) (string, game.ActionType, error) {
+func (g *Game) adminCastleGarden(c *gin.Context, cu *user.User) (string, game.ActionType, error) {
 	log.Debugf(msgEnter)
 	defer log.Debugf(msgExit)
 
@@ -179,15 +193,11 @@ func (g *Game) adminCastleGarden(c *gin.Context) (string, game.ActionType, error
 	// 	return "", game.None, err
 	// }
 
-	g.CastleGarden[irish] = cg.Irish
-	g.CastleGarden[german] = cg.German
-	g.CastleGarden[italian] = cg.Italian
-	g.CastleGarden[english] = cg.English
-
-	return "", game.Save, nil
+	v := nationalsForm(cg.Irish, cg.English, cg.German, cg.Italian)
+	return g.adminEdit(cu, c.PostForm("reason"), adminCastleGardenTarget, 0, v)
 }
 
-func (g *Game) adminImmigrantBag(c *gin.Context) (string, game.ActionType, error) {
+func (g *Game) adminImmigrantBag(c *gin.Context, cu *user.User) (string, game.ActionType, error) {
 	log.Debugf(msgEnter)
 	defer log.Debugf(msgExit)
 
@@ -207,10 +217,16 @@ func (g *Game) adminImmigrantBag(c *gin.Context) (string, game.ActionType, error
 	// 	return "", game.None, err
 	// }
 
-	g.Bag[irish] = cg.Irish
-	g.Bag[german] = cg.German
-	g.Bag[italian] = cg.Italian
-	g.Bag[english] = cg.English
+	v := nationalsForm(cg.Irish, cg.English, cg.German, cg.Italian)
+	return g.adminEdit(cu, c.PostForm("reason"), adminBagTarget, 0, v)
+}
 
-	return "", game.Save, nil
+// nationalsForm provides counts of immigrants received from an admin form, keyed as by chipsView.
+func nationalsForm(irishCount, englishCount, germanCount, italianCount int) map[string]int {
+	return map[string]int{
+		irish.String():   irishCount,
+		english.String(): englishCount,
+		german.String():  germanCount,
+		italian.String(): italianCount,
+	}
 }
@@ -0,0 +1,752 @@
+package tammany
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SlothNinja/game"
+	"github.com/SlothNinja/log"
+	"github.com/SlothNinja/restful"
+	"github.com/SlothNinja/user"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gob.Register(new(adminEditEntry))
+	registerEntry("adminEdit", new(adminEditEntry))
+}
+
+// Targets of admin edits, named after the update actions performing the edits.
+const (
+	adminStateTarget        = "game-state"
+	adminPlayerTarget       = "player"
+	adminWardTarget         = "ward"
+	adminCastleGardenTarget = "castle-garden"
+	adminBagTarget          = "immigrant-bag"
+)
+
+// adminPasswordField names the change of the password, whose values an admin edit never logs.
+const adminPasswordField = "Password"
+
+// AdminChange provides the values of a field before and after an admin edit.
+type AdminChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// adminEditEntry records an admin edit, including the admin, the reason, and the edited target before and after the edit.
+// Edits applied together share a Batch, and are rolled back together.
+// Edits logged before batches were recorded have Batch zero, and are rolled back alone.
+type adminEditEntry struct {
+	*Entry
+	AdminID    int64
+	AdminName  string
+	Reason     string
+	Target     string
+	TargetID   int
+	Before     json.RawMessage
+	After      json.RawMessage
+	Changes    []*AdminChange
+	Batch      int
+	RollbackOf int
+	RolledBack bool
+
+	// password holds the password of the game before an edit of the game state, until the edit is logged.
+	password string
+}
+
+// cubeRule governs the change of immigrant cube totals by a batch of admin edits.
+type cubeRule int
+
+const (
+	// conserveCubes rejects edits changing the cube totals.
+	conserveCubes cubeRule = iota
+
+	// balanceCubes returns cubes added to or removed from the Castle Garden and the wards to the bag.
+	balanceCubes
+
+	// changeCubes permits edits to change the cube totals.
+	changeCubes
+)
+
+// adminStateView provides the header fields edited by adminState.
+// The password is excluded, so as not to reveal it in the game log.
+type adminStateView struct {
+	Title         string
+	Phase         game.Phase
+	Round         int
+	NumPlayers    int
+	CreatorID     int64
+	UserIDS       []int64
+	CPUserIndices game.UserIndices
+	WinnerIDS     game.UserIndices
+	Status        game.Status
+}
+
+// adminPlayerView provides the player fields edited by adminPlayer.
+type adminPlayerView struct {
+	Score            int
+	Chips            map[string]int
+	PlayedChips      map[string]int
+	PerformedAction  bool
+	Candidate        bool
+	UsedOffice       bool
+	PlacedBosses     int
+	PlacedImmigrants int
+	HasBid           bool
+}
+
+// adminWardView provides the ward fields edited by adminWard.
+type adminWardView struct {
+	Immigrants map[string]int
+	Bosses     map[int]int
+	Resolved   bool
+	LockedUp   bool
+}
+
+func chipsView(cs map[nationality]int) map[string]int {
+	m := make(map[string]int, len(cs))
+	for _, n := range nationalities() {
+		m[n.String()] = cs[n]
+	}
+	return m
+}
+
+func applyChipsView(cs map[nationality]int, m map[string]int) {
+	for _, n := range nationalities() {
+		cs[n] = m[n.String()]
+	}
+}
+
+// adminView provides a JSON snapshot of the fields of the target that admins may edit.
+func (g *Game) adminView(target string, id int) (json.RawMessage, error) {
+	var v interface{}
+	switch target {
+	case adminStateTarget:
+		v = &adminStateView{
+			Title:         g.Title,
+			Phase:         g.Phase,
+			Round:         g.Round,
+			NumPlayers:    g.NumPlayers,
+			CreatorID:     g.CreatorID,
+			UserIDS:       g.UserIDS,
+			CPUserIndices: g.CPUserIndices,
+			WinnerIDS:     g.WinnerIDS,
+			Status:        g.Status,
+		}
+	case adminPlayerTarget:
+		p := g.PlayerByID(id)
+		if p == nil {
+			return nil, g.verror(ErrInvalidValue, "Player %d does not exist.", id)
+		}
+		v = &adminPlayerView{
+			Score:            p.Score,
+			Chips:            chipsView(p.Chips),
+			PlayedChips:      chipsView(p.PlayedChips),
+			PerformedAction:  p.PerformedAction,
+			Candidate:        p.Candidate,
+			UsedOffice:       p.UsedOffice,
+			PlacedBosses:     p.PlacedBosses,
+			PlacedImmigrants: p.PlacedImmigrants,
+			HasBid:           p.HasBid,
+		}
+	case adminWardTarget:
+		w := g.wardByID(wardID(id))
+		if w == nil {
+			return nil, g.verror(ErrInvalidValue, "Ward %d does not exist.", id)
+		}
+		bosses := make(map[int]int, len(g.Players()))
+		for _, p := range g.Players() {
+			bosses[p.ID()] = w.BossesFor(p)
+		}
+		v = &adminWardView{
+			Immigrants: chipsView(w.Immigrants),
+			Bosses:     bosses,
+			Resolved:   w.Resolved,
+			LockedUp:   w.LockedUp,
+		}
+	case adminCastleGardenTarget:
+		v = chipsView(g.CastleGarden)
+	case adminBagTarget:
+		v = chipsView(g.Bag)
+	default:
+		return nil, fmt.Errorf("unknown admin edit target %q", target)
+	}
+	return json.Marshal(v)
+}
+
+// applyAdminView restores the fields of the target from a snapshot provided by adminView.
+func (g *Game) applyAdminView(target string, id int, raw json.RawMessage) error {
+	switch target {
+	case adminStateTarget:
+		v := new(adminStateView)
+		if err := json.Unmarshal(raw, v); err != nil {
+			return err
+		}
+		g.Title = v.Title
+		g.Phase = v.Phase
+		g.Round = v.Round
+		g.NumPlayers = v.NumPlayers
+		g.CreatorID = v.CreatorID
+		g.UserIDS = v.UserIDS
+		g.CPUserIndices = v.CPUserIndices
+		g.WinnerIDS = v.WinnerIDS
+		g.Status = v.Status
+	case adminPlayerTarget:
+		p := g.PlayerByID(id)
+		if p == nil {
+			return g.verror(ErrInvalidValue, "Player %d does not exist.", id)
+		}
+		v := new(adminPlayerView)
+		if err := json.Unmarshal(raw, v); err != nil {
+			return err
+		}
+		p.Score = v.Score
+		applyChipsView(p.Chips, v.Chips)
+		applyChipsView(p.PlayedChips, v.PlayedChips)
+		p.PerformedAction = v.PerformedAction
+		p.Candidate = v.Candidate
+		p.UsedOffice = v.UsedOffice
+		p.PlacedBosses = v.PlacedBosses
+		p.PlacedImmigrants = v.PlacedImmigrants
+		p.HasBid = v.HasBid
+	case adminWardTarget:
+		w := g.wardByID(wardID(id))
+		if w == nil {
+			return g.verror(ErrInvalidValue, "Ward %d does not exist.", id)
+		}
+		v := new(adminWardView)
+		if err := json.Unmarshal(raw, v); err != nil {
+			return err
+		}
+		applyChipsView(w.Immigrants, v.Immigrants)
+		for _, p := range g.Players() {
+			w.Bosses[p.ID()] = v.Bosses[p.ID()]
+		}
+		w.Resolved = v.Resolved
+		w.LockedUp = v.LockedUp
+	case adminCastleGardenTarget, adminBagTarget:
+		var v map[string]int
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		if target == adminBagTarget {
+			applyChipsView(g.Bag, v)
+		} else {
+			applyChipsView(g.CastleGarden, v)
+		}
+	default:
+		return fmt.Errorf("unknown admin edit target %q", target)
+	}
+	return nil
+}
+
+// adminChanges compares two snapshots provided by adminView, field by field.
+// Fields holding maps are compared key by key, e.g. Chips.Irish.
+func adminChanges(before, after json.RawMessage) ([]*AdminChange, error) {
+	flatten := func(raw json.RawMessage) (map[string]json.RawMessage, error) {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, err
+		}
+
+		flat := make(map[string]json.RawMessage, len(fields))
+		for name, value := range fields {
+			var sub map[string]json.RawMessage
+			if err := json.Unmarshal(value, &sub); err != nil || sub == nil {
+				flat[name] = value
+				continue
+			}
+			for key, subValue := range sub {
+				flat[name+"."+key] = subValue
+			}
+		}
+		return flat, nil
+	}
+
+	b, err := flatten(before)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := flatten(after)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []*AdminChange
+	for _, name := range names {
+		var bv, av interface{}
+		_ = json.Unmarshal(b[name], &bv)
+		_ = json.Unmarshal(a[name], &av)
+		if reflect.DeepEqual(bv, av) {
+			continue
+		}
+		changes = append(changes, &AdminChange{Field: name, Before: string(b[name]), After: string(a[name])})
+	}
+	return changes, nil
+}
+
+// beginAdminEdit snapshots the target of an admin edit before the edit.
+func (g *Game) beginAdminEdit(cu *user.User, reason, target string, id int) (*adminEditEntry, error) {
+	before, err := g.adminView(target, id)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &adminEditEntry{
+		Entry:      g.newEntry(),
+		AdminID:    cu.ID(),
+		AdminName:  cu.Name,
+		Reason:     strings.TrimSpace(reason),
+		Target:     target,
+		TargetID:   id,
+		Before:     before,
+		RollbackOf: -1,
+	}
+	switch target {
+	case adminPlayerTarget:
+		e.PlayerID = id
+	case adminStateTarget:
+		e.password = g.Password
+	}
+	return e, nil
+}
+
+// logAdminEdit snapshots the target after the edit and logs the edit, provided the edit changed the target.
+func (g *Game) logAdminEdit(e *adminEditEntry) (bool, error) {
+	after, err := g.adminView(e.Target, e.TargetID)
+	if err != nil {
+		return false, err
+	}
+	e.After = after
+
+	e.Changes, err = adminChanges(e.Before, e.After)
+	if err != nil {
+		return false, err
+	}
+	if e.Target == adminStateTarget && e.password != g.Password {
+		e.Changes = append(e.Changes, &AdminChange{Field: adminPasswordField})
+	}
+	e.password = ""
+	if len(e.Changes) == 0 {
+		return false, nil
+	}
+
+	g.Log = append(g.Log, e)
+	return true, nil
+}
+
+// revertAdminEdits restores the targets of the edits, latest edit first.
+func (g *Game) revertAdminEdits(es []*adminEditEntry) {
+	for i := len(es) - 1; i >= 0; i-- {
+		if err := g.applyAdminView(es[i].Target, es[i].TargetID, es[i].Before); err != nil {
+			log.Warningf("unable to revert admin edit of %s %d: %v", es[i].Target, es[i].TargetID, err)
+		}
+		if es[i].Target == adminStateTarget {
+			g.Password = es[i].password
+		}
+	}
+}
+
+// adminEdits validates and applies the edits as a whole, logging an admin edit for each edit that changed its target.
+// No edit is applied, unless every edit is valid and the edits together conserve the immigrant cubes.
+func (g *Game) adminEdits(cu *user.User, reason string, eds []*AdminEdit) ([]*adminEditEntry, error) {
+	return g.adminEditBatch(cu, reason, eds, conserveCubes, nil)
+}
+
+// adminEditBatch validates and applies the edits as a whole, changing the cube totals as permitted by rule,
+// and logs an admin edit for each edit that changed its target, all in a new batch.
+// If rollbackOf is not nil, the edit eds[i] rolls back the logged edit having index rollbackOf[i].
+func (g *Game) adminEditBatch(cu *user.User, reason string, eds []*AdminEdit, rule cubeRule, rollbackOf []int) ([]*adminEditEntry, error) {
+	if !cu.IsAdmin() {
+		return nil, g.verror(ErrNotAdmin, "Only an admin may edit the game.")
+	}
+
+	fes := FieldErrors{}
+	if strings.TrimSpace(reason) == "" {
+		fes.add("reason", "is required")
+	}
+	if len(eds) == 0 {
+		fes.add("edits", "is required")
+	}
+	for i, ed := range eds {
+		g.validateAdminEdit(fmt.Sprintf("edits[%d]", i), ed, &fes)
+	}
+	if len(fes) > 0 {
+		return nil, fes
+	}
+
+	totals := g.cubeTotals()
+	es := make([]*adminEditEntry, 0, len(eds)+1)
+	for i, ed := range eds {
+		e, err := g.beginAdminEdit(cu, reason, ed.Target, ed.ID)
+		if err != nil {
+			g.revertAdminEdits(es)
+			return nil, err
+		}
+		if rollbackOf != nil {
+			e.RollbackOf = rollbackOf[i]
+		}
+		es = append(es, e)
+
+		err = g.applyAdminView(ed.Target, ed.ID, ed.Values)
+		if err != nil {
+			g.revertAdminEdits(es)
+			return nil, err
+		}
+		if ed.Target == adminStateTarget && ed.password != nil {
+			g.Password = *ed.password
+		}
+	}
+
+	switch rule {
+	case balanceCubes:
+		e, err := g.beginAdminEdit(cu, reason, adminBagTarget, 0)
+		if err != nil {
+			g.revertAdminEdits(es)
+			return nil, err
+		}
+		es = append(es, e)
+		g.balanceCubeTotals(totals, &fes)
+	case conserveCubes:
+		g.validateCubeTotals(totals, &fes)
+	}
+	if len(fes) > 0 {
+		g.revertAdminEdits(es)
+		return nil, fes
+	}
+
+	batch := g.nextAdminEditBatch()
+	var logged []*adminEditEntry
+	for _, e := range es {
+		e.Batch = batch
+		changed, err := g.logAdminEdit(e)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			logged = append(logged, e)
+		}
+	}
+	return logged, nil
+}
+
+// adminEdit applies an edit of the target from an admin form.
+func (g *Game) adminEdit(cu *user.User, reason, target string, id int, v interface{}) (string, game.ActionType, error) {
+	values, err := json.Marshal(v)
+	if err != nil {
+		return "", game.None, err
+	}
+	return g.adminFormEdit(cu, reason, &AdminEdit{Target: target, ID: id, Values: values})
+}
+
+// adminFormEdit applies the edit from an admin form.
+func (g *Game) adminFormEdit(cu *user.User, reason string, ed *AdminEdit) (string, game.ActionType, error) {
+
+	// Cubes added to or removed from the Castle Garden or a ward are returned to the bag,
+	// whereas editing the bag corrects the cube totals.
+	rule := conserveCubes
+	switch ed.Target {
+	case adminWardTarget, adminCastleGardenTarget:
+		rule = balanceCubes
+	case adminBagTarget:
+		rule = changeCubes
+	}
+
+	_, err := g.adminEditBatch(cu, reason, []*AdminEdit{ed}, rule, nil)
+	if fes, ok := err.(FieldErrors); ok {
+		return "", game.None, g.verror(ErrInvalidValue, "Invalid edit: %v", fes)
+	}
+	if err != nil {
+		return "", game.None, err
+	}
+	return "", game.Save, nil
+}
+
+// adminEditsForm applies a batch of admin edits posted as a form, as recorded for applyAdminEdits.
+func (g *Game) adminEditsForm(c *gin.Context, cu *user.User) (string, game.ActionType, error) {
+	log.Debugf(msgEnter)
+	defer log.Debugf(msgExit)
+
+	var eds []*AdminEdit
+	err := json.Unmarshal([]byte(c.PostForm("edits")), &eds)
+	if err != nil {
+		return "", game.None, g.verror(ErrInvalidValue, "Invalid value received for edits.")
+	}
+
+	_, err = g.adminEdits(cu, c.PostForm("reason"), eds)
+	if fes, ok := err.(FieldErrors); ok {
+		return "", game.None, g.verror(ErrInvalidValue, "Invalid edits: %v", fes)
+	}
+	if err != nil {
+		return "", game.None, err
+	}
+	return "", game.Save, nil
+}
+
+// adminRollback restores the targets of the batch of a logged admin edit to their state before the batch.
+// The rollback is itself logged as a batch of admin edits, and may be rolled back in turn.
+func (g *Game) adminRollback(c *gin.Context, cu *user.User) (string, game.ActionType, error) {
+	log.Debugf(msgEnter)
+	defer log.Debugf(msgExit)
+
+	i, err := strconv.Atoi(c.PostForm("entry"))
+	if err != nil || i < 0 || i >= len(g.Log) {
+		return "", game.None, g.verror(ErrInvalidEntry, "Invalid value received for entry.")
+	}
+
+	e, ok := g.Log[i].(*adminEditEntry)
+	if !ok {
+		return "", game.None, g.verror(ErrInvalidEntry, "Entry %d is not an admin edit.", i)
+	}
+
+	batch := g.adminEditBatchOf(i)
+	for _, index := range batch {
+		if g.Log[index].(*adminEditEntry).RolledBack {
+			return "", game.None, g.verror(ErrInvalidEntry, "Entry %d was already rolled back.", index)
+		}
+	}
+
+	reason := c.PostForm("reason")
+	if strings.TrimSpace(reason) == "" {
+		reason = fmt.Sprintf("Rollback of entry %d: %s", i, e.Reason)
+	}
+
+	// Restore the targets latest edit first, so a target edited repeatedly within the batch regains its state before the batch.
+	eds := make([]*AdminEdit, len(batch))
+	rollbackOf := make([]int, len(batch))
+	for j, index := range batch {
+		be := g.Log[index].(*adminEditEntry)
+		k := len(batch) - 1 - j
+		eds[k] = &AdminEdit{Target: be.Target, ID: be.TargetID, Values: be.Before}
+		rollbackOf[k] = index
+	}
+
+	es, err := g.adminEditBatch(cu, reason, eds, conserveCubes, rollbackOf)
+	if fes, ok := err.(FieldErrors); ok {
+		return "", game.None, g.verror(ErrInvalidValue, "Unable to roll back entry %d: %v", i, fes)
+	}
+	if err != nil {
+		return "", game.None, err
+	}
+	if len(es) == 0 {
+		return "", game.None, g.verror(ErrInvalidEntry, "Entry %d needs no rollback, since its target is unchanged since before the edit.", i)
+	}
+
+	for _, index := range batch {
+		g.Log[index].(*adminEditEntry).RolledBack = true
+	}
+	return "", game.Save, nil
+}
+
+// nextAdminEditBatch provides the batch of the next admin edits.
+func (g *Game) nextAdminEditBatch() int {
+	batch := 0
+	for _, e := range g.Log {
+		if ae, ok := e.(*adminEditEntry); ok && ae.Batch > batch {
+			batch = ae.Batch
+		}
+	}
+	return batch + 1
+}
+
+// adminEditBatchOf provides the indices of the logged admin edits of the batch of the admin edit having index i.
+func (g *Game) adminEditBatchOf(i int) []int {
+	e := g.Log[i].(*adminEditEntry)
+	if e.Batch == 0 {
+		return []int{i}
+	}
+
+	var batch []int
+	for j, le := range g.Log {
+		if ae, ok := le.(*adminEditEntry); ok && ae.Batch == e.Batch {
+			batch = append(batch, j)
+		}
+	}
+	return batch
+}
+
+func (e *adminEditEntry) targetName(g *Game) string {
+	switch e.Target {
+	case adminStateTarget:
+		return g.T("the game state")
+	case adminPlayerTarget:
+		return g.NameByPID(e.TargetID)
+	case adminWardTarget:
+		return g.T("ward %d", e.TargetID)
+	case adminCastleGardenTarget:
+		return g.T("the Castle Garden")
+	case adminBagTarget:
+		return g.T("the immigrant bag")
+	default:
+		return e.Target
+	}
+}
+
+func (e *adminEditEntry) changesText(g *Game) string {
+	ss := make([]string, len(e.Changes))
+	for i, change := range e.Changes {
+		if change.Field == adminPasswordField {
+			ss[i] = g.T("the password")
+			continue
+		}
+		ss[i] = g.T("%s from %s to %s", change.Field, change.Before, change.After)
+	}
+	return g.toSentence(ss)
+}
+
+func (e *adminEditEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *adminEditEntry) Text(g *Game) string {
+	if e.RollbackOf != -1 {
+		return g.T("Admin %s rolled back %s, changing %s. Reason: %s", e.AdminName, e.targetName(g), e.changesText(g), e.Reason)
+	}
+	return g.T("Admin %s edited %s, changing %s. Reason: %s", e.AdminName, e.targetName(g), e.changesText(g), e.Reason)
+}
+
+func (e *adminEditEntry) Event() *Event {
+	ev := e.event(e)
+	if e.Target == adminWardTarget {
+		ev.WardID = e.TargetID
+	}
+	ev.Reason = e.Reason
+	ev.Changes = e.Changes
+	return ev
+}
+
+// adminViews provides the fields of every target that admins may edit, as accepted by applyAdminEdits.
+func (client *Client) adminViews(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil || !cu.IsAdmin() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only an admin may edit the game."})
+			return
+		}
+
+		g := gameFrom(c)
+		if g == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": ErrGameNotFound.Error()})
+			return
+		}
+
+		var views []*AdminEdit
+		add := func(target string, id int) {
+			if err == nil {
+				var values json.RawMessage
+				values, err = g.adminView(target, id)
+				views = append(views, &AdminEdit{Target: target, ID: id, Values: values})
+			}
+		}
+
+		add(adminStateTarget, 0)
+		for _, p := range g.Players() {
+			add(adminPlayerTarget, p.ID())
+		}
+		for _, w := range g.Wards {
+			add(adminWardTarget, int(w.ID))
+		}
+		add(adminCastleGardenTarget, 0)
+		add(adminBagTarget, 0)
+
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"edits": views})
+	}
+}
+
+type adminEditsRequest struct {
+	Reason string       `json:"reason"`
+	Edits  []*AdminEdit `json:"edits"`
+}
+
+// applyAdminEdits validates and applies a batch of admin edits, responding with field-level errors for invalid edits.
+func (client *Client) applyAdminEdits(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil || !cu.IsAdmin() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only an admin may edit the game."})
+			return
+		}
+
+		g := gameFrom(c)
+		if g == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": ErrGameNotFound.Error()})
+			return
+		}
+
+		req := new(adminEditsRequest)
+		err = c.ShouldBindJSON(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		es, err := g.adminEdits(cu, req.Reason, req.Edits)
+		if fes, ok := err.(FieldErrors); ok {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"errors": fes})
+			return
+		}
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		edits, err := json.Marshal(req.Edits)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		m := newMove("admin-edits")
+		m.Year = g.Year()
+		m.Params = url.Values{"reason": {req.Reason}, "edits": {string(edits)}}
+		g.recordMove(m)
+
+		err = client.save(c, g, cu)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+
+		evs := make([]*Event, len(es))
+		for i, e := range es {
+			evs[i] = e.Event()
+		}
+		c.JSON(http.StatusOK, gin.H{"edits": evs})
+	}
+}
@@ -0,0 +1,227 @@
+package tammany
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// wardEdit provides an edit of the ward adding count immigrants of nationality n.
+func wardEdit(t *testing.T, g *Game, w *Ward, n nationality, count int) *AdminEdit {
+	raw, err := g.adminView(adminWardTarget, int(w.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := new(adminWardView)
+	err = json.Unmarshal(raw, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.Immigrants[n.String()] += count
+
+	values, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &AdminEdit{Target: adminWardTarget, ID: int(w.ID), Values: values}
+}
+
+// bagEdit provides an edit of the bag adding count immigrants of nationality n.
+func bagEdit(t *testing.T, g *Game, n nationality, count int) *AdminEdit {
+	v := chipsView(g.Bag)
+	v[n.String()] += count
+
+	values, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &AdminEdit{Target: adminBagTarget, Values: values}
+}
+
+func TestAdminEditsValidation(t *testing.T) {
+	g := newTestGame(t, 3)
+	p := g.Players()[0]
+	score, logLen := p.Score, len(g.Log)
+
+	_, err := g.adminEdits(testAdmin(), "fix", []*AdminEdit{
+		{Target: adminPlayerTarget, ID: p.ID(), Values: json.RawMessage(`{"Score": -1}`)},
+		{Target: "unknown", Values: json.RawMessage(`{}`)},
+	})
+
+	fes, ok := err.(FieldErrors)
+	if !ok {
+		t.Fatalf("got error %v, want field errors", err)
+	}
+	fields := make(map[string]bool)
+	for _, fe := range fes {
+		fields[fe.Field] = true
+	}
+	for _, field := range []string{"edits[0].values.Score", "edits[1].target"} {
+		if !fields[field] {
+			t.Errorf("missing field error for %s in %v", field, fes)
+		}
+	}
+	if p.Score != score || len(g.Log) != logLen {
+		t.Errorf("invalid edits changed the game")
+	}
+
+	_, err = g.adminEdits(testAdmin(), "", []*AdminEdit{{Target: adminPlayerTarget, ID: p.ID(), Values: json.RawMessage(`{"Score": 5}`)}})
+	if _, ok := err.(FieldErrors); !ok {
+		t.Errorf("got error %v, want field error for missing reason", err)
+	}
+}
+
+func TestAdminEditsConserveCubes(t *testing.T) {
+	g := newTestGame(t, 3)
+	w := g.Wards[0]
+	before := g.cubeTotals()
+
+	_, err := g.adminEdits(testAdmin(), "fix", []*AdminEdit{wardEdit(t, g, w, irish, 1)})
+	if _, ok := err.(FieldErrors); !ok {
+		t.Fatalf("got error %v, want field error for changed cube totals", err)
+	}
+
+	es, err := g.adminEdits(testAdmin(), "fix", []*AdminEdit{wardEdit(t, g, w, irish, 1), bagEdit(t, g, irish, -1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(es) != 2 || es[0].Batch != es[1].Batch {
+		t.Fatalf("got %d edits, want 2 edits of one batch", len(es))
+	}
+	if after := g.cubeTotals(); after[irish] != before[irish] {
+		t.Errorf("got %d Irish cubes, want %d", after[irish], before[irish])
+	}
+}
+
+func TestAdminRollbackOfBatch(t *testing.T) {
+	g := newTestGame(t, 3)
+	w := g.Wards[0]
+	wardBefore, bagBefore := w.Immigrants[irish], g.Bag[irish]
+
+	es, err := g.adminEdits(testAdmin(), "move a cube", []*AdminEdit{wardEdit(t, g, w, irish, 1), bagEdit(t, g, irish, -1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rolling back either edit of the batch rolls back both.
+	index := len(g.Log) - 1
+	c := formContext(t, url.Values{"entry": {strconv.Itoa(index)}})
+	_, _, err = g.adminRollback(c, testAdmin())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Immigrants[irish] != wardBefore || g.Bag[irish] != bagBefore {
+		t.Errorf("got %d Irish in ward and %d in bag, want %d and %d", w.Immigrants[irish], g.Bag[irish], wardBefore, bagBefore)
+	}
+	for _, e := range es {
+		if !e.RolledBack {
+			t.Errorf("edit of %s was not marked rolled back", e.Target)
+		}
+	}
+
+	rollbacks := g.adminEditBatchOf(len(g.Log) - 1)
+	if len(rollbacks) != 2 {
+		t.Fatalf("got %d rollback edits, want 2", len(rollbacks))
+	}
+	for _, i := range rollbacks {
+		if e := g.Log[i].(*adminEditEntry); e.RollbackOf != index && e.RollbackOf != index-1 {
+			t.Errorf("rollback edit of %s rolls back entry %d, want an entry of the batch", e.Target, e.RollbackOf)
+		}
+	}
+
+	_, _, err = g.adminRollback(formContext(t, url.Values{"entry": {strconv.Itoa(index - 1)}}), testAdmin())
+	if !isValidationError(err) {
+		t.Errorf("got error %v, want validation error for repeated rollback", err)
+	}
+}
+
+func TestAdminFormEdits(t *testing.T) {
+	g := newTestGame(t, 3)
+	w := g.Wards[0]
+	before := g.cubeTotals()
+	bag := g.Bag[irish]
+
+	// Cubes added to a ward by its form are taken from the bag.
+	v := wardEdit(t, g, w, irish, 2)
+	_, _, err := g.adminEdit(testAdmin(), "fix", adminWardTarget, int(w.ID), v.Values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Bag[irish] != bag-2 || g.cubeTotals()[irish] != before[irish] {
+		t.Errorf("got %d Irish in bag, want %d", g.Bag[irish], bag-2)
+	}
+
+	// The bag form corrects the cube totals.
+	_, _, err = g.adminEdit(testAdmin(), "fix", adminBagTarget, 0, bagEdit(t, g, irish, 1).Values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.cubeTotals()[irish]; got != before[irish]+1 {
+		t.Errorf("got %d Irish cubes, want %d", got, before[irish]+1)
+	}
+}
+
+func TestAdminEditTextInGerman(t *testing.T) {
+	g := newTestGame(t, 3)
+	bag := g.Bag[irish]
+
+	_, _, err := g.adminEdit(testAdmin(), "Zählfehler", adminBagTarget, 0, bagEdit(t, g, irish, 1).Values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := g.Log[len(g.Log)-1].Text(g.withLanguage("de"))
+	want := "hat den Einwandererbeutel bearbeitet und Irish von " + strconv.Itoa(bag) + " auf " + strconv.Itoa(bag+1) + " geändert. Grund: Zählfehler"
+	if !strings.Contains(text, want) {
+		t.Errorf("got %q, want it to contain %q", text, want)
+	}
+}
+
+func TestAdminStateLogsPasswordChange(t *testing.T) {
+	g := newTestGame(t, 3)
+	g.Password = "old"
+
+	cps := make([]string, len(g.CPUserIndices))
+	for i, index := range g.CPUserIndices {
+		cps[i] = strconv.Itoa(index)
+	}
+	c := formContext(t, url.Values{
+		"cp-user-indices": cps,
+		"reason":          {"new password"},
+		"title":           {g.Title},
+		"phase":           {strconv.Itoa(int(g.Phase))},
+		"round":           {strconv.Itoa(g.Round)},
+		"num-players":     {strconv.Itoa(g.NumPlayers)},
+		"status":          {strconv.Itoa(int(g.Status))},
+		"password":        {"s3cret"},
+	})
+	_, _, err := g.adminState(c, testAdmin())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Password != "s3cret" {
+		t.Fatalf("got password %q, want %q", g.Password, "s3cret")
+	}
+
+	e, ok := g.Log[len(g.Log)-1].(*adminEditEntry)
+	if !ok || len(e.Changes) != 1 || e.Changes[0].Field != adminPasswordField {
+		t.Fatalf("got entry %v, want the password change logged", g.Log[len(g.Log)-1])
+	}
+	if text := e.Text(g); !strings.Contains(text, "changing the password.") {
+		t.Errorf("got %q, want the password change noted", text)
+	}
+
+	bs, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"old", "s3cret"} {
+		if strings.Contains(string(bs), secret) {
+			t.Errorf("logged entry %s holds the password %q", bs, secret)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package tammany
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldError identifies an invalid field of an admin edit.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldErrors provides the invalid fields of an admin edit.
+type FieldErrors []*FieldError
+
+func (fes FieldErrors) Error() string {
+	ss := make([]string, len(fes))
+	for i, fe := range fes {
+		ss[i] = fmt.Sprintf("%s: %s", fe.Field, fe.Message)
+	}
+	return strings.Join(ss, "; ")
+}
+
+func (fes *FieldErrors) add(field, format string, args ...interface{}) {
+	*fes = append(*fes, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// AdminEdit provides the values of the fields of a target, as provided by adminView, to which an admin edit sets the target.
+type AdminEdit struct {
+	Target string          `json:"target"`
+	ID     int             `json:"id"`
+	Values json.RawMessage `json:"values"`
+
+	// password replaces the password of the game by an edit of the game state from the admin form.
+	// It is kept apart from Values, so as to keep it out of the game log and the recorded action.
+	password *string
+}
+
+func decodeStrict(raw json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// validateAdminEdit validates the admin edit, reporting invalid fields relative to field.
+func (g *Game) validateAdminEdit(field string, ed *AdminEdit, fes *FieldErrors) {
+	values := field + ".values"
+	if len(ed.Values) == 0 {
+		fes.add(values, "is required")
+		return
+	}
+
+	switch ed.Target {
+	case adminStateTarget:
+		v := new(adminStateView)
+		if err := decodeStrict(ed.Values, v); err != nil {
+			fes.add(values, "%v", err)
+			return
+		}
+		v.validate(g, values, fes)
+	case adminPlayerTarget:
+		if g.PlayerByID(ed.ID) == nil {
+			fes.add(field+".id", "player %d does not exist", ed.ID)
+			return
+		}
+		v := new(adminPlayerView)
+		if err := decodeStrict(ed.Values, v); err != nil {
+			fes.add(values, "%v", err)
+			return
+		}
+		v.validate(values, fes)
+	case adminWardTarget:
+		if g.wardByID(wardID(ed.ID)) == nil {
+			fes.add(field+".id", "ward %d does not exist", ed.ID)
+			return
+		}
+		v := new(adminWardView)
+		if err := decodeStrict(ed.Values, v); err != nil {
+			fes.add(values, "%v", err)
+			return
+		}
+		v.validate(g, values, fes)
+	case adminCastleGardenTarget, adminBagTarget:
+		var v map[string]int
+		if err := decodeStrict(ed.Values, &v); err != nil {
+			fes.add(values, "%v", err)
+			return
+		}
+		validateCounts(values, v, fes)
+	default:
+		fes.add(field+".target", "unknown target %q", ed.Target)
+	}
+}
+
+func (v *adminStateView) validate(g *Game, field string, fes *FieldErrors) {
+	if _, ok := phaseNames[v.Phase]; !ok {
+		fes.add(field+".Phase", "unknown phase %d", v.Phase)
+	}
+	if v.Round < 0 {
+		fes.add(field+".Round", "must not be negative")
+	}
+	if v.NumPlayers < 0 || v.NumPlayers > 5 {
+		fes.add(field+".NumPlayers", "must be between 0 and 5")
+	}
+	for i, index := range v.CPUserIndices {
+		if index < 0 || index >= len(v.UserIDS) {
+			fes.add(fmt.Sprintf("%s.CPUserIndices[%d]", field, i), "user index %d does not exist", index)
+		}
+	}
+	for i, index := range v.WinnerIDS {
+		if index < 0 || index >= len(v.UserIDS) {
+			fes.add(fmt.Sprintf("%s.WinnerIDS[%d]", field, i), "user index %d does not exist", index)
+		}
+	}
+}
+
+func (v *adminPlayerView) validate(field string, fes *FieldErrors) {
+	if v.Score < 0 {
+		fes.add(field+".Score", "must not be negative")
+	}
+	validateCounts(field+".Chips", v.Chips, fes)
+	validateCounts(field+".PlayedChips", v.PlayedChips, fes)
+	for chip, count := range v.PlayedChips {
+		if count > v.Chips[chip] {
+			fes.add(field+".PlayedChips."+chip, "must not exceed the %d %s chips held", v.Chips[chip], chip)
+		}
+	}
+	if v.PlacedBosses < 0 || v.PlacedBosses > 2 {
+		fes.add(field+".PlacedBosses", "must be between 0 and 2")
+	}
+	if v.PlacedImmigrants < 0 || v.PlacedImmigrants > 1 {
+		fes.add(field+".PlacedImmigrants", "must be between 0 and 1")
+	}
+}
+
+func (v *adminWardView) validate(g *Game, field string, fes *FieldErrors) {
+	validateCounts(field+".Immigrants", v.Immigrants, fes)
+	for pid, count := range v.Bosses {
+		f := fmt.Sprintf("%s.Bosses.%d", field, pid)
+		switch {
+		case g.PlayerByID(pid) == nil:
+			fes.add(f, "player %d does not exist", pid)
+		case count < 0:
+			fes.add(f, "must not be negative")
+		}
+	}
+}
+
+// validateCounts validates counts of chips or immigrants keyed by nationality, as provided by chipsView.
+func validateCounts(field string, counts map[string]int, fes *FieldErrors) {
+	known := chipsView(nil)
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		count := counts[key]
+		if _, ok := known[key]; !ok {
+			fes.add(field+"."+key, "unknown nationality")
+			continue
+		}
+		if count < 0 {
+			fes.add(field+"."+key, "must not be negative")
+		}
+	}
+}
+
+// cubeTotals provides the count of immigrant cubes by nationality in the bag, the Castle Garden, and the wards.
+// An immigrant being moved is returned to the bag until placed, so the totals are constant during a game.
+func (g *Game) cubeTotals() map[nationality]int {
+	totals := make(map[nationality]int)
+	for _, n := range g.Nationalities() {
+		totals[n] = g.Bag[n] + g.CastleGarden[n]
+		for _, w := range g.Wards {
+			totals[n] += w.Immigrants[n]
+		}
+	}
+	return totals
+}
+
+// balanceCubeTotals returns to the bag the cubes by which the totals differ from before,
+// reporting nationalities lacking cubes in the bag to return.
+func (g *Game) balanceCubeTotals(before map[nationality]int, fes *FieldErrors) {
+	after := g.cubeTotals()
+	for _, n := range g.Nationalities() {
+		g.Bag[n] -= after[n] - before[n]
+		if g.Bag[n] < 0 {
+			fes.add("edits", "the edits add %d %s cubes, but the bag holds only %d", after[n]-before[n], n, g.Bag[n]+after[n]-before[n])
+		}
+	}
+}
+
+// validateCubeTotals reports nationalities whose cube totals differ from before.
+func (g *Game) validateCubeTotals(before map[nationality]int, fes *FieldErrors) {
+	after := g.cubeTotals()
+	for _, n := range g.Nationalities() {
+		if after[n] != before[n] {
+			fes.add("edits", "the edits change the number of %s cubes from %d to %d; cubes must be moved between the bag, the Castle Garden, and the wards", n, before[n], after[n])
+		}
+	}
+}
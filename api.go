@@ -0,0 +1,352 @@
+package tammany
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/SlothNinja/game"
+	"github.com/SlothNinja/restful"
+	"github.com/SlothNinja/user"
+	"github.com/gin-gonic/gin"
+)
+
+const moveKey = "Move"
+
+// GameSummary summarizes a game listed by the API.
+type GameSummary struct {
+	ID             int64     `json:"id"`
+	Title          string    `json:"title"`
+	Status         string    `json:"status"`
+	Year           int       `json:"year"`
+	Phase          string    `json:"phase"`
+	Players        []string  `json:"players"`
+	CurrentPlayers []string  `json:"currentPlayers"`
+	YourTurn       bool      `json:"yourTurn"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// BoardView provides the state of a game served by the API.
+// Players are identified by id, so player 0 is noted P1 in move notation.
+type BoardView struct {
+	ID                 int64          `json:"id"`
+	Title              string         `json:"title"`
+	Status             string         `json:"status"`
+	Year               int            `json:"year"`
+	Phase              string         `json:"phase"`
+	CurrentPlayers     []int          `json:"currentPlayers"`
+	CurrentWard        int            `json:"currentWard,omitempty"`
+	ImmigrantInTransit string         `json:"immigrantInTransit,omitempty"`
+	Players            []*PlayerView  `json:"players"`
+	Wards              []*WardView    `json:"wards"`
+	CastleGarden       map[string]int `json:"castleGarden"`
+	Bag                map[string]int `json:"bag"`
+	Actions            []string       `json:"actions"`
+	SpectatorDelay     int            `json:"spectatorDelay,omitempty"`
+}
+
+// PlayerView provides the state of a player served by the API.
+type PlayerView struct {
+	ID               int            `json:"id"`
+	Name             string         `json:"name"`
+	Score            int            `json:"score"`
+	Office           string         `json:"office,omitempty"`
+	Chips            map[string]int `json:"chips"`
+	PlayedChips      map[string]int `json:"playedChips"`
+	SlanderChip      bool           `json:"slanderChip"`
+	PlacedBosses     int            `json:"placedBosses"`
+	PlacedImmigrants int            `json:"placedImmigrants"`
+	PerformedAction  bool           `json:"performedAction"`
+	UsedOffice       bool           `json:"usedOffice"`
+}
+
+// WardView provides the state of a ward served by the API.
+// Bosses maps player ids to the bosses the player has in the ward.
+type WardView struct {
+	ID         int            `json:"id"`
+	Immigrants map[string]int `json:"immigrants"`
+	Bosses     map[int]int    `json:"bosses"`
+	LockedUp   bool           `json:"lockedUp"`
+	Resolved   bool           `json:"resolved"`
+}
+
+// boardView provides the state of the game served by the API to the user.
+func (g *Game) boardView(cu *user.User) *BoardView {
+	v := &BoardView{
+		ID:             g.ID(),
+		Title:          g.Title,
+		Status:         g.Status.String(),
+		Year:           g.Year(),
+		Phase:          phaseNames[g.Phase],
+		CastleGarden:   chipsView(g.CastleGarden),
+		Bag:            chipsView(g.Bag),
+		Actions:        g.actionsFor(cu),
+		SpectatorDelay: g.SpectatorDelay,
+	}
+
+	if g.CurrentWardID != noWardID {
+		v.CurrentWard = int(g.CurrentWardID)
+	}
+	if g.ImmigrantInTransit != noNationality {
+		v.ImmigrantInTransit = g.ImmigrantInTransit.String()
+	}
+	for _, p := range g.CurrentPlayers() {
+		v.CurrentPlayers = append(v.CurrentPlayers, p.ID())
+	}
+
+	for _, p := range g.Players() {
+		pv := &PlayerView{
+			ID:               p.ID(),
+			Name:             g.NameFor(p),
+			Score:            p.Score,
+			Chips:            chipsView(p.Chips),
+			PlayedChips:      chipsView(p.PlayedChips),
+			SlanderChip:      p.CanSlanderIn(g.Term()),
+			PlacedBosses:     p.PlacedBosses,
+			PlacedImmigrants: p.PlacedImmigrants,
+			PerformedAction:  p.PerformedAction,
+			UsedOffice:       p.UsedOffice,
+		}
+		if p.Office != noOffice {
+			pv.Office = p.Office.String()
+		}
+		v.Players = append(v.Players, pv)
+	}
+
+	for _, w := range g.Wards {
+		wv := &WardView{
+			ID:         int(w.ID),
+			Immigrants: chipsView(w.Immigrants),
+			Bosses:     make(map[int]int),
+			LockedUp:   w.LockedUp,
+			Resolved:   w.Resolved,
+		}
+		for pid, count := range w.Bosses {
+			if count > 0 {
+				wv.Bosses[pid] = count
+			}
+		}
+		v.Wards = append(v.Wards, wv)
+	}
+	sort.Slice(v.Wards, func(i, j int) bool { return v.Wards[i].ID < v.Wards[j].ID })
+	return v
+}
+
+// actionsFor provides the recorded actions of the game as shown to the user.
+// Only admins see the values of admin actions, such as the edits made and the reasons given.
+func (g *Game) actionsFor(cu *user.User) []string {
+	if cu.IsAdmin() {
+		return g.Actions
+	}
+
+	as := make([]string, len(g.Actions))
+	for i, a := range g.Actions {
+		m, err := ParseMove(a)
+		switch {
+		case err != nil:
+			as[i] = "?"
+		case adminActions[m.Action] != nil:
+			m.Params = nil
+			as[i] = m.String()
+		default:
+			as[i] = a
+		}
+	}
+	return as
+}
+
+// gameSummary summarizes the game having header h for the user.
+func gameSummary(h *game.Header, cu *user.User) *GameSummary {
+	gs := &GameSummary{
+		ID:        h.ID(),
+		Title:     h.Title,
+		Status:    h.Status.String(),
+		Year:      h.Round,
+		Phase:     phaseNames[h.Phase],
+		Players:   h.UserNames,
+		YourTurn:  h.IsCurrentPlayer(cu),
+		UpdatedAt: h.UpdatedAt,
+	}
+	for _, index := range h.CPUserIndices {
+		if index >= 0 && index < len(h.UserNames) {
+			gs.CurrentPlayers = append(gs.CurrentPlayers, h.UserNames[index])
+		}
+	}
+	return gs
+}
+
+// withCurrentUID filters the games listed by GetFiltered to those of the current user.
+func (client *Client) withCurrentUID(c *gin.Context) {
+	client.Log.Debugf(msgEnter)
+	defer client.Log.Debugf(msgExit)
+
+	cu, err := client.User.Current(c)
+	if err != nil || cu == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "You must be logged in to list your games."})
+		return
+	}
+	c.Params = append(c.Params, gin.Param{Key: "uid", Value: strconv.FormatInt(cu.ID(), 10)})
+}
+
+// apiIndex lists the games of the current user having the requested status.
+func (client *Client) apiIndex(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		gs := []*GameSummary{}
+		for _, g := range game.GamersFrom(c) {
+			gs = append(gs, gameSummary(g.GetHeader(), cu))
+		}
+		c.JSON(http.StatusOK, gin.H{"games": gs})
+	}
+}
+
+// apiShow provides the state of the game.
+func (client *Client) apiShow(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		g := gameFrom(c)
+		if g == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": ErrGameNotFound.Error()})
+			return
+		}
+		cu, err := client.User.Current(c)
+		if err != nil {
+			client.Log.Debugf(err.Error())
+		}
+		c.JSON(http.StatusOK, g.boardView(cu))
+	}
+}
+
+// apiMoveValues parses the posted move, and provides its form values in place of the body of the request,
+// so fetch and update see the move as if posted by the game page.
+func (client *Client) apiMoveValues(c *gin.Context) {
+	client.Log.Debugf(msgEnter)
+	defer client.Log.Debugf(msgExit)
+
+	obj := struct {
+		Move    string `json:"move"`
+		Confirm bool   `json:"confirm"`
+	}{}
+
+	err := c.ShouldBindJSON(&obj)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	m, err := ParseMove(obj.Move)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, validationJSON(err))
+		return
+	}
+	if adminActions[m.Action] != nil {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin actions may not be posted as moves."})
+		return
+	}
+
+	vs := m.Values()
+	if m.Action == finishAction && obj.Confirm {
+		vs.Set("action", "confirm-finish")
+	}
+	c.Request.Form, c.Request.PostForm = vs, vs
+	c.Set(moveKey, m)
+}
+
+func moveFrom(c *gin.Context) (m *Move) {
+	m, _ = c.Value(moveKey).(*Move)
+	return
+}
+
+// validateAPIMove validates that the move is noted for the player and ward of the game.
+func (g *Game) validateAPIMove(m *Move, cu *user.User) error {
+	cp := g.CurrentPlayerFor(cu)
+	switch {
+	case m.PlayerID != noPlayerID && (cp == nil || cp.ID() != m.PlayerID):
+		return g.verror(ErrNotCurrentPlayer, "The move is noted for player %d, who is not your current player.", m.PlayerID+1)
+	case m.Action == bidAction && m.WardID != noWardID && m.WardID != g.CurrentWardID:
+		return g.verror(ErrWrongWard, "The election is in ward %d, not ward %d.", g.CurrentWardID, m.WardID)
+	default:
+		return nil
+	}
+}
+
+// apiMove performs the move posted in move notation, responding with the resulting state of the game.
+// As on the game page, moves are cached until the player finishes the turn.
+func (client *Client) apiMove(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		g, m := gameFrom(c), moveFrom(c)
+		if g == nil || m == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": ErrGameNotFound.Error()})
+			return
+		}
+
+		err = g.validateAPIMove(m, cu)
+		if err == nil {
+			if m.Action == finishAction {
+				err = client.finishAndSave(c, g, cu)
+			} else {
+				err = client.apiUpdate(c, g, cu)
+			}
+		}
+
+		switch {
+		case isValidationError(err):
+			c.JSON(http.StatusUnprocessableEntity, validationJSON(err))
+		case err != nil:
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		case g.InOfficeWarningSubPhase():
+			c.JSON(http.StatusOK, gin.H{
+				"warning": g.T("You may still use your office. Finish again with confirm to end your turn."),
+				"board":   g.boardView(cu),
+				"text":    g.TextBoard(cu),
+			})
+		default:
+			c.JSON(http.StatusOK, gin.H{
+				"board":   g.boardView(cu),
+				"text":    g.TextBoard(cu),
+				"notices": restful.NoticesFrom(c),
+			})
+		}
+	}
+}
+
+// apiUpdate performs the move, as posted to update, caching or saving the game as update would.
+func (client *Client) apiUpdate(c *gin.Context, g *Game, cu *user.User) error {
+	_, actionType, err := g.update(c, cu)
+	if err != nil {
+		return err
+	}
+
+	switch actionType {
+	case game.Cache:
+		g.recordUpdate(c, cu)
+		client.Cache.SetDefault(g.UndoKey(cu), g)
+	case game.Save:
+		g.recordUpdate(c, cu)
+		return client.save(c, g, cu)
+	case game.Undo:
+		client.Cache.Delete(g.UndoKey(cu))
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package tammany
+
+import (
+	"testing"
+
+	"github.com/SlothNinja/user"
+)
+
+func TestBoardViewHidesAdminValues(t *testing.T) {
+	g := newTestGame(t, 3)
+	playTurns(t, newTestClient(), g, 1)
+
+	admin := "Y1: ADMIN game-state reason=lost+points title=renamed"
+	g.Actions = append(g.Actions, admin)
+	n := len(g.Actions)
+
+	for _, cu := range []*user.User{nil, user.New(g.UserIDS[0]), user.New(50)} {
+		as := g.boardView(cu).Actions
+		if len(as) != n || as[n-1] != "Y1: ADMIN game-state" {
+			t.Errorf("got actions %q for user %v, want the admin action without its values", as, cu)
+		}
+		if as[0] != g.Actions[0] {
+			t.Errorf("got action %q for user %v, want %q", as[0], cu, g.Actions[0])
+		}
+	}
+
+	if as := g.boardView(testAdmin()).Actions; as[n-1] != admin {
+		t.Errorf("got admin action %q for an admin, want %q", as[n-1], admin)
+	}
+}
@@ -0,0 +1,334 @@
+package tammany
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SlothNinja/game"
+	"github.com/SlothNinja/mlog"
+	"github.com/SlothNinja/restful"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	archiveFormat        = "tammany-game"
+	archiveFormatVersion = 1
+	archiveParam         = "archive"
+)
+
+// gameArchive provides a self-describing, portable representation of a complete game.
+// Email addresses and passwords of users are not archived.
+type gameArchive struct {
+	Format        string           `json:"format"`
+	FormatVersion int              `json:"formatVersion"`
+	ExportedAt    time.Time        `json:"exportedAt"`
+	ID            int64            `json:"id"`
+	Header        *archiveHeader   `json:"header"`
+	Players       []*archivePlayer `json:"players"`
+	State         json.RawMessage  `json:"state"`
+	Messages      []*mlog.Message  `json:"messages"`
+}
+
+type archiveHeader struct {
+	Title         string           `json:"title"`
+	Turn          int              `json:"turn"`
+	Phase         game.Phase       `json:"phase"`
+	SubPhase      game.SubPhase    `json:"subPhase"`
+	Round         int              `json:"round"`
+	NumPlayers    int              `json:"numPlayers"`
+	CreatorID     int64            `json:"creatorId"`
+	CreatorSID    string           `json:"creatorSId"`
+	CreatorName   string           `json:"creatorName"`
+	UserIDS       []int64          `json:"userIds"`
+	UserSIDS      []string         `json:"userSIds"`
+	UserNames     []string         `json:"userNames"`
+	OrderIDS      game.UserIndices `json:"orderIds"`
+	CPUserIndices game.UserIndices `json:"cpUserIndices"`
+	WinnerIDS     game.UserIndices `json:"winnerIndices"`
+	Status        game.Status      `json:"status"`
+	Progress      string           `json:"progress"`
+	Options       []string         `json:"options"`
+	OptString     string           `json:"optString"`
+	StartedAt     time.Time        `json:"startedAt"`
+	CreatedAt     time.Time        `json:"createdAt"`
+	UpdatedAt     time.Time        `json:"updatedAt"`
+	EndedAt       time.Time        `json:"endedAt"`
+}
+
+// archivePlayer maps a player of the game to the user controlling the player.
+type archivePlayer struct {
+	PlayerID  int    `json:"playerId"`
+	UserIndex int    `json:"userIndex"`
+	UserID    int64  `json:"userId"`
+	UserName  string `json:"userName"`
+	Color     string `json:"color"`
+}
+
+func (g *Game) archive(ms []*mlog.Message) (*gameArchive, error) {
+	s := *g.State
+	s.Actions = notedActions(g.Actions)
+	state, err := encodeState(&s)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &gameArchive{
+		Format:        archiveFormat,
+		FormatVersion: archiveFormatVersion,
+		ExportedAt:    time.Now(),
+		ID:            g.ID(),
+		Header: &archiveHeader{
+			Title:         g.Title,
+			Turn:          g.Turn,
+			Phase:         g.Phase,
+			SubPhase:      g.SubPhase,
+			Round:         g.Round,
+			NumPlayers:    g.NumPlayers,
+			CreatorID:     g.CreatorID,
+			CreatorSID:    g.CreatorSID,
+			CreatorName:   g.CreatorName,
+			UserIDS:       g.UserIDS,
+			UserSIDS:      g.UserSIDS,
+			UserNames:     g.UserNames,
+			OrderIDS:      g.OrderIDS,
+			CPUserIndices: g.CPUserIndices,
+			WinnerIDS:     g.WinnerIDS,
+			Status:        g.Status,
+			Progress:      g.Progress,
+			Options:       g.Options,
+			OptString:     g.OptString,
+			StartedAt:     g.StartedAt,
+			CreatedAt:     g.CreatedAt,
+			UpdatedAt:     g.UpdatedAt,
+			EndedAt:       g.EndedAt,
+		},
+		State:    state,
+		Messages: ms,
+	}
+
+	for _, p := range g.Players() {
+		a.Players = append(a.Players, &archivePlayer{
+			PlayerID:  p.ID(),
+			UserIndex: p.ID(),
+			UserID:    g.UserIDFor(p),
+			UserName:  g.NameFor(p),
+			Color:     p.Color().String(),
+		})
+	}
+	return a, nil
+}
+
+// notedActions provides the actions, noting admin actions anew, as admin actions noted before their values were
+// restricted may hold passwords and email addresses.
+func notedActions(as []string) []string {
+	noted := make([]string, len(as))
+	for i, a := range as {
+		noted[i] = a
+		if m, err := ParseMove(a); err == nil && adminActions[m.Action] != nil {
+			noted[i] = m.String()
+		}
+	}
+	return noted
+}
+
+// restore sets the header and state of game g from archive a.
+func (g *Game) restore(a *gameArchive) error {
+	if a.Format != archiveFormat {
+		return fmt.Errorf("unrecognized archive format %q", a.Format)
+	}
+
+	if a.FormatVersion < 1 || a.FormatVersion > archiveFormatVersion {
+		return fmt.Errorf("unsupported archive format version %d", a.FormatVersion)
+	}
+
+	if a.Header == nil {
+		return fmt.Errorf("archive missing header")
+	}
+
+	s, _, err := decodeState(a.State)
+	if err != nil {
+		return err
+	}
+
+	if len(s.Playerers) != len(a.Header.UserIDS) && a.Header.Status != game.Recruiting {
+		return fmt.Errorf("archive has %d players but %d users", len(s.Playerers), len(a.Header.UserIDS))
+	}
+
+	h := a.Header
+	g.Title = h.Title
+	g.Turn = h.Turn
+	g.Phase = h.Phase
+	g.SubPhase = h.SubPhase
+	g.Round = h.Round
+	g.NumPlayers = h.NumPlayers
+	g.CreatorID = h.CreatorID
+	g.CreatorSID = h.CreatorSID
+	g.CreatorName = h.CreatorName
+	g.UserIDS = h.UserIDS
+	g.UserSIDS = h.UserSIDS
+	g.UserNames = h.UserNames
+	g.OrderIDS = h.OrderIDS
+	g.CPUserIndices = h.CPUserIndices
+	g.WinnerIDS = h.WinnerIDS
+	g.Status = h.Status
+	g.Options = h.Options
+	g.OptString = h.OptString
+	g.StartedAt = h.StartedAt
+	g.EndedAt = h.EndedAt
+	g.State = s
+	return nil
+}
+
+func (client *Client) exportGame(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		id, err := getID(c)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.Redirect(http.StatusSeeOther, homePath)
+			return
+		}
+
+		g := New(c, id)
+		err = client.dsGet(c, g)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.Redirect(http.StatusSeeOther, homePath)
+			return
+		}
+
+		cu, err := client.User.Current(c)
+		if err != nil {
+			client.Log.Debugf(err.Error())
+		}
+
+		g, err = client.spectatorView(c, g, cu)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			restful.AddErrorf(c, err.Error())
+			c.Redirect(http.StatusSeeOther, showPath(prefix, c.Param(hParam)))
+			return
+		}
+
+		var ms []*mlog.Message
+		ml, err := client.Store.GetMLog(c, id)
+		if err != nil {
+			client.Log.Warningf("unable to get message log for game %d: %v", id, err)
+		} else {
+			ms = ml.Messages
+		}
+
+		a, err := g.archive(ms)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			restful.AddErrorf(c, err.Error())
+			c.Redirect(http.StatusSeeOther, showPath(prefix, c.Param(hParam)))
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%d.json"`, archiveFormat, id))
+		c.IndentedJSON(http.StatusOK, a)
+	}
+}
+
+// importGame creates a new game from an archive provided either as the request body or as an uploaded file.
+func (client *Client) importGame(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil || !cu.IsAdmin() {
+			restful.AddErrorf(c, "Only an admin may import games.")
+			c.Redirect(http.StatusSeeOther, homePath)
+			return
+		}
+
+		a, err := archiveFrom(c)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			restful.AddErrorf(c, "Unable to read archive: %v", err)
+			c.Redirect(http.StatusSeeOther, recruitingPath(prefix))
+			return
+		}
+
+		g := New(c, 0)
+		err = g.restore(a)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			restful.AddErrorf(c, "Unable to import archive: %v", err)
+			c.Redirect(http.StatusSeeOther, recruitingPath(prefix))
+			return
+		}
+
+		err = client.createFromArchive(c, g, a.Messages)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			restful.AddErrorf(c, "Unable to import archive: %v", err)
+			c.Redirect(http.StatusSeeOther, recruitingPath(prefix))
+			return
+		}
+
+		hid := strconv.FormatInt(g.ID(), 10)
+		restful.AddNoticef(c, "<div>Imported game %d as game %s.</div>", a.ID, hid)
+		c.Redirect(http.StatusSeeOther, showPath(prefix, hid))
+	}
+}
+
+func (client *Client) createFromArchive(c *gin.Context, g *Game, ms []*mlog.Message) error {
+	err := g.encode(c)
+	if err != nil {
+		return err
+	}
+
+	err = client.Store.Create(c, g, ms)
+	if err != nil {
+		return err
+	}
+
+	// Archives hold no snapshots, so the imported game may be replayed only from its import.
+	if g.Status != game.Recruiting {
+		g.takeSnapshot()
+		client.putSnapshots(c, g)
+	}
+	return nil
+}
+
+func archiveFrom(c *gin.Context) (*gameArchive, error) {
+	var (
+		bs  []byte
+		err error
+	)
+
+	fh, ferr := c.FormFile(archiveParam)
+	if ferr == nil {
+		f, err := fh.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		bs, err = ioutil.ReadAll(f)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		bs, err = c.GetRawData()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	a := new(gameArchive)
+	err = json.Unmarshal(bs, a)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
@@ -0,0 +1,43 @@
+package tammany
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestArchiveOmitsEmailsAndPasswords(t *testing.T) {
+	g := newTestGame(t, 3)
+	playTurns(t, newTestClient(), g, 1)
+	g.Password = "s3cret"
+
+	// Admin actions noted before their values were restricted noted the password and email addresses.
+	legacy := "Y1: ADMIN game-state password=s3cret reason=r title=t user-emails=player1%40example.com"
+	g.Actions = append(g.Actions, legacy)
+
+	a, err := g.archive(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bs, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"s3cret", "example.com"} {
+		if strings.Contains(string(bs), secret) {
+			t.Errorf("archive holds %q", secret)
+		}
+	}
+
+	s, _, err := decodeState(a.State)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.Actions[len(s.Actions)-1], "Y1: ADMIN game-state reason=r title=t"; got != want {
+		t.Errorf("got archived action %q, want %q", got, want)
+	}
+	if len(s.Actions) != len(g.Actions) || s.Actions[0] != g.Actions[0] {
+		t.Errorf("got archived actions %q, want %q", s.Actions, g.Actions)
+	}
+}
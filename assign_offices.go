@@ -8,19 +8,20 @@ import (
 	"github.com/SlothNinja/game"
 	"github.com/SlothNinja/log"
 	"github.com/SlothNinja/restful"
-	"github.com/SlothNinja/sn"
 	"github.com/SlothNinja/user"
 	"github.com/gin-gonic/gin"
 )
 
 func init() {
 	gob.RegisterName("*game.assignedOfficeEntry", new(assignedOfficeEntry))
+	registerEntry("assignedOffice", new(assignedOfficeEntry))
 }
 
 func (client *Client) startCityOfficesPhase(c *gin.Context, g *Game) ([]*contest.Contest, error) {
 	client.Log.Debugf(msgEnter)
 	defer client.Log.Debugf(msgExit)
 
+	g.markPhaseBoundary()
 	for _, player := range g.Players() {
 		g.beginningOfTurnResetFor(player)
 	}
@@ -73,10 +74,20 @@ func (g *Game) newAssignedOfficeEntryFor(p *Player, o office, op *Player) *assig
 }
 
 func (e *assignedOfficeEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	return restful.HTML("%s assigned %s the office of %s.",
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *assignedOfficeEntry) Text(g *Game) string {
+	return g.T("%s assigned %s the office of %s.",
 		g.NameByPID(e.PlayerID), g.NameByPID(e.OtherPlayerID), e.Office)
 }
 
+func (e *assignedOfficeEntry) Event() *Event {
+	ev := e.event(e)
+	ev.Office = e.Office.eventString()
+	return ev
+}
+
 func (g *Game) allPlayersHaveOffice() bool {
 	for _, p := range g.Players() {
 		if !p.hasAnOffice() {
@@ -99,33 +110,33 @@ func (g *Game) validateAssignOffice(c *gin.Context, cu *user.User) (*Player, off
 	o, p := g.getOffice(c), g.playerBySID(c.PostForm("pid"))
 	switch {
 	case !g.IsCurrentPlayer(cu):
-		return nil, noOffice, sn.NewVError("Only the current player can select an office.")
+		return nil, noOffice, g.verror(ErrNotCurrentPlayer, "Only the current player can select an office.")
 	case o == noOffice:
-		return nil, noOffice, sn.NewVError("Invalid office assigned.")
+		return nil, noOffice, g.verror(ErrInvalidValue, "Invalid office assigned.")
 	case g.CurrentPlayer().PerformedAction:
-		return nil, noOffice, sn.NewVError("You have already performed an action.")
+		return nil, noOffice, g.verror(ErrAlreadyActed, "You have already performed an action.")
 	case g.officeAssigned(o):
-		return nil, noOffice, sn.NewVError("%s office has already been assigned.", o)
+		return nil, noOffice, g.verror(ErrOfficeAssigned, "%s office has already been assigned.", o)
 	case !officeValues.include(o):
-		return nil, noOffice, sn.NewVError("Invalid value received for office.", o)
+		return nil, noOffice, g.verror(ErrInvalidValue, "Invalid value received for office.")
 	case p == nil:
-		return nil, noOffice, sn.NewVError("Invalid value received for player.")
+		return nil, noOffice, g.verror(ErrInvalidValue, "Invalid value received for player.")
 	case p.Office != noOffice:
-		return nil, noOffice, sn.NewVError("%s has already been assigned the office of %s", g.NameFor(p), p.Office)
+		return nil, noOffice, g.verror(ErrOfficeAssigned, "%s has already been assigned the office of %s", g.NameFor(p), p.Office)
 	case g.Phase == assignDeputyMayor && g.mayor() == nil:
-		return nil, noOffice, sn.NewVError("There is no Mayor to appoint a Deputy Mayor.")
+		return nil, noOffice, g.verror(ErrMissingOfficer, "There is no Mayor to appoint a Deputy Mayor.")
 	case g.Phase == assignDeputyMayor && !cp.isMayor() && !cu.IsAdmin():
-		return nil, noOffice, sn.NewVError("You are not the Mayor and therefore can't assign offices.")
+		return nil, noOffice, g.verror(ErrWrongOffice, "You are not the Mayor and therefore can't assign offices.")
 	case g.Phase == assignDeputyMayor && o != deputyMayor:
-		return nil, noOffice, sn.NewVError("The mayor must first appoint a Deputy Mayor.")
+		return nil, noOffice, g.verror(ErrActionIncomplete, "The mayor must first appoint a Deputy Mayor.")
 	case g.Phase == deputyMayorAssignOffice && g.deputyMayor() == nil:
-		return nil, noOffice, sn.NewVError("There is no Deputy Mayor to assign offices.")
+		return nil, noOffice, g.verror(ErrMissingOfficer, "There is no Deputy Mayor to assign offices.")
 	case g.Phase == deputyMayorAssignOffice && !cp.isDeputyMayor() && !cu.IsAdmin():
-		return nil, noOffice, sn.NewVError("You are not the Deputy Mayor and therefore can't assign offices.")
+		return nil, noOffice, g.verror(ErrWrongOffice, "You are not the Deputy Mayor and therefore can't assign offices.")
 	case g.Phase == assignCityOffices && g.mayor() == nil:
-		return nil, noOffice, sn.NewVError("There is no Mayor to assign offices.")
+		return nil, noOffice, g.verror(ErrMissingOfficer, "There is no Mayor to assign offices.")
 	case g.Phase == assignCityOffices && !cp.isMayor() && !cu.IsAdmin():
-		return nil, noOffice, sn.NewVError("You are not the Mayor and therefore can't assign offices.")
+		return nil, noOffice, g.verror(ErrWrongOffice, "You are not the Mayor and therefore can't assign offices.")
 	default:
 		return p, o, nil
 	}
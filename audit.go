@@ -0,0 +1,310 @@
+package tammany
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SlothNinja/game"
+	"github.com/gin-gonic/gin"
+)
+
+// auditStatuses identifies the statuses of games audited by auditGames.
+var auditStatuses = []game.Status{
+	game.Running,
+	game.Completed,
+}
+
+const (
+	auditAdminEdit    = "adminEdit"
+	auditAction       = "action"
+	auditLog          = "log"
+	auditScore        = "score"
+	auditChips        = "chips"
+	auditBosses       = "bosses"
+	auditImmigrants   = "immigrants"
+	auditCastleGarden = "castleGarden"
+	auditBag          = "bag"
+)
+
+// AuditReport provides the divergences between a game and the game as replayed by the rules from its recorded actions.
+type AuditReport struct {
+	GameID      int64              `json:"gameId"`
+	Title       string             `json:"title"`
+	Entries     int                `json:"entries"`
+	Actions     int                `json:"actions"`
+	Consistent  bool               `json:"consistent"`
+	Divergences []*AuditDivergence `json:"divergences"`
+	AuditedAt   time.Time          `json:"auditedAt"`
+}
+
+// AuditDivergence provides a quantity for which the replayed game diverges from the game, an action that failed to replay,
+// or an admin edit of the game, which the rules do not provide.
+// EntryIndex identifies the entry of the game log at which the replayed log diverges.
+// EntryIndex is -1 if the divergence concerns no entry of the log.
+type AuditDivergence struct {
+	EntryIndex int    `json:"entryIndex"`
+	Entry      string `json:"entry,omitempty"`
+	Action     string `json:"action,omitempty"`
+	Field      string `json:"field"`
+	PlayerID   int    `json:"playerId"`
+	WardID     int    `json:"wardId,omitempty"`
+	Chip       string `json:"chip,omitempty"`
+	Immigrant  string `json:"immigrant,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	Derived    int    `json:"derived"`
+	Recorded   int    `json:"recorded"`
+	Message    string `json:"message"`
+}
+
+// Audit replays the recorded actions of the game by the rules, from the first snapshot of the game,
+// and reports where the replayed game diverges from the game, e.g. due to an illegal move or an unrecorded edit.
+// Admin edits are replayed as recorded, so each logged admin edit is reported as well.
+func (client *Client) Audit(c *gin.Context, g *Game) (*AuditReport, error) {
+	r := &AuditReport{
+		GameID:      g.ID(),
+		Title:       g.Title,
+		Entries:     len(g.Log),
+		Actions:     len(g.Actions),
+		Divergences: []*AuditDivergence{},
+		AuditedAt:   time.Now(),
+	}
+
+	snaps, err := client.snapshotsOf(c, g)
+	if err != nil {
+		return nil, err
+	}
+	if len(snaps) == 0 {
+		return nil, fmt.Errorf("game %d has no snapshot from which to audit its actions", g.ID())
+	}
+
+	g.auditAdminEdits(r)
+
+	g2, err := client.replayFrom(c, g, snaps[0], len(g.Actions))
+	var rerr *replayError
+	switch {
+	case errors.As(err, &rerr):
+		r.diverged(&AuditDivergence{
+			EntryIndex: -1,
+			Action:     rerr.Action,
+			Field:      auditAction,
+			PlayerID:   noPlayerID,
+			Message:    fmt.Sprintf("Action %d %q fails to replay: %v", rerr.Index, rerr.Action, rerr.Err),
+		})
+	case err != nil:
+		return nil, err
+	default:
+		g.auditLog(r, g2)
+		g.auditState(r, g2)
+	}
+
+	r.Consistent = len(r.Divergences) == 0
+	return r, nil
+}
+
+func (r *AuditReport) diverged(d *AuditDivergence) {
+	r.Divergences = append(r.Divergences, d)
+}
+
+// auditAdminEdits reports the admin edits logged by the game, including rollbacks of edits.
+func (g *Game) auditAdminEdits(r *AuditReport) {
+	for i, e := range g.Log {
+		ae, ok := e.(*adminEditEntry)
+		if !ok {
+			continue
+		}
+
+		d := &AuditDivergence{
+			EntryIndex: i,
+			Entry:      ae.Text(g),
+			Field:      auditAdminEdit,
+			PlayerID:   noPlayerID,
+			Reason:     ae.Reason,
+			Message:    fmt.Sprintf("Admin %s edited %s. Reason: %s", ae.AdminName, ae.targetName(g), ae.Reason),
+		}
+		switch ae.Target {
+		case adminPlayerTarget:
+			d.PlayerID = ae.TargetID
+		case adminWardTarget:
+			d.WardID = ae.TargetID
+		}
+		r.diverged(d)
+	}
+}
+
+// auditLog reports the first entry at which the log of the replayed game g2 diverges from the log of the game.
+// Rewinds are not replayed, so their entries are skipped.
+func (g *Game) auditLog(r *AuditReport, g2 *Game) {
+	j := 0
+	for i, e := range g.Log {
+		if _, ok := e.(*rewoundEntry); ok {
+			continue
+		}
+
+		recorded := e.Text(g)
+		if j >= len(g2.Log) {
+			r.diverged(&AuditDivergence{EntryIndex: i, Entry: recorded, Field: auditLog, PlayerID: noPlayerID,
+				Derived: len(g2.Log), Recorded: len(g.Log),
+				Message: fmt.Sprintf("The replayed log ends before entry %d.", i)})
+			return
+		}
+
+		if replayed := g2.Log[j].Text(g2); replayed != recorded {
+			r.diverged(&AuditDivergence{EntryIndex: i, Entry: recorded, Field: auditLog, PlayerID: noPlayerID,
+				Message: fmt.Sprintf("The replayed log reads %q at entry %d.", replayed, i)})
+			return
+		}
+		j++
+	}
+
+	if j < len(g2.Log) {
+		r.diverged(&AuditDivergence{EntryIndex: -1, Entry: g2.Log[j].Text(g2), Field: auditLog, PlayerID: noPlayerID,
+			Derived: len(g2.Log), Recorded: len(g.Log),
+			Message: fmt.Sprintf("The replayed log continues with %q.", g2.Log[j].Text(g2))})
+	}
+}
+
+// auditState reports the scores, favor chips, bosses and immigrants for which the replayed game g2 diverges from the game.
+func (g *Game) auditState(r *AuditReport, g2 *Game) {
+	check := func(d *AuditDivergence, derived, recorded int, what string) {
+		if derived == recorded {
+			return
+		}
+		d.EntryIndex, d.Derived, d.Recorded = -1, derived, recorded
+		d.Message = fmt.Sprintf("%s: %d recorded, but %d when replayed.", what, recorded, derived)
+		r.diverged(d)
+	}
+
+	for _, p := range g.Players() {
+		p2 := g2.PlayerByID(p.ID())
+		if p2 == nil {
+			continue
+		}
+
+		name := g.NameFor(p)
+		check(&AuditDivergence{Field: auditScore, PlayerID: p.ID()}, p2.Score, p.Score, "Score of "+name)
+		for _, n := range g.Nationalities() {
+			check(&AuditDivergence{Field: auditChips, PlayerID: p.ID(), Chip: n.String()}, p2.Chips[n], p.Chips[n],
+				fmt.Sprintf("%s favor chips of %s", n, name))
+		}
+		for _, w := range g.Wards {
+			if w2 := g2.wardByID(w.ID); w2 != nil {
+				check(&AuditDivergence{Field: auditBosses, PlayerID: p.ID(), WardID: int(w.ID)}, w2.BossesFor(p2), w.BossesFor(p),
+					fmt.Sprintf("Bosses of %s in ward %d", name, w.ID))
+			}
+		}
+	}
+
+	for _, n := range g.Nationalities() {
+		for _, w := range g.Wards {
+			if w2 := g2.wardByID(w.ID); w2 != nil {
+				check(&AuditDivergence{Field: auditImmigrants, PlayerID: noPlayerID, WardID: int(w.ID), Immigrant: n.String()},
+					w2.Immigrants[n], w.Immigrants[n], fmt.Sprintf("%s immigrants in ward %d", n, w.ID))
+			}
+		}
+		check(&AuditDivergence{Field: auditCastleGarden, PlayerID: noPlayerID, Immigrant: n.String()},
+			g2.CastleGarden[n], g.CastleGarden[n], fmt.Sprintf("%s immigrants in the Castle Garden", n))
+		check(&AuditDivergence{Field: auditBag, PlayerID: noPlayerID, Immigrant: n.String()},
+			g2.Bag[n], g.Bag[n], fmt.Sprintf("%s immigrants in the bag", n))
+	}
+}
+
+func (client *Client) auditGame(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil || !cu.IsAdmin() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only an admin may audit games."})
+			return
+		}
+
+		id, err := getID(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		g := New(c, id)
+		err = client.dsGet(c, g)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		r, err := client.Audit(c, g)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, r)
+	}
+}
+
+type auditFailure struct {
+	ID    int64  `json:"id"`
+	Error string `json:"error"`
+}
+
+type auditSummary struct {
+	Checked      int            `json:"checked"`
+	Inconsistent []*AuditReport `json:"inconsistent"`
+	Failed       []auditFailure `json:"failed"`
+}
+
+// auditGames audits every running and completed game, reporting the games whose state diverges from their log.
+func (client *Client) auditGames(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil || !cu.IsAdmin() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only an admin may audit games."})
+			return
+		}
+
+		summary := auditSummary{Inconsistent: []*AuditReport{}, Failed: []auditFailure{}}
+		for _, status := range auditStatuses {
+			gs, err := client.Store.ListByStatus(c, status)
+			if err != nil {
+				client.Log.Errorf(err.Error())
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "summary": summary})
+				return
+			}
+
+			for _, g := range gs {
+				summary.Checked++
+				s, _, err := decodeState(g.SavedState)
+				if err != nil {
+					client.Log.Warningf("unable to decode state of game %d: %v", g.ID(), err)
+					summary.Failed = append(summary.Failed, auditFailure{ID: g.ID(), Error: err.Error()})
+					continue
+				}
+				g.State = s
+
+				err = client.init(c, g)
+				if err != nil {
+					client.Log.Warningf("unable to initialize game %d: %v", g.ID(), err)
+					summary.Failed = append(summary.Failed, auditFailure{ID: g.ID(), Error: err.Error()})
+					continue
+				}
+
+				r, err := client.Audit(c, g)
+				if err != nil {
+					client.Log.Warningf("unable to audit game %d: %v", g.ID(), err)
+					summary.Failed = append(summary.Failed, auditFailure{ID: g.ID(), Error: err.Error()})
+					continue
+				}
+				if !r.Consistent {
+					summary.Inconsistent = append(summary.Inconsistent, r)
+				}
+			}
+		}
+		c.JSON(http.StatusOK, summary)
+	}
+}
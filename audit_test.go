@@ -0,0 +1,121 @@
+package tammany
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+// playTurns plays n turns of the actions phase of the game.
+func playTurns(t *testing.T, client *Client, g *Game, n int) {
+	c := testContext()
+	for turn := 0; turn < n; turn++ {
+		for _, m := range turnMoves(g) {
+			err := client.applyMove(c, g, m)
+			if err != nil {
+				t.Fatalf("turn %d: %q: %v", turn, m, err)
+			}
+		}
+	}
+}
+
+func TestAuditOfCleanGame(t *testing.T) {
+	client, c := newTestClient(), testContext()
+	g := newTestGame(t, 3)
+	playTurns(t, client, g, 6)
+
+	r, err := client.Audit(c, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Consistent || len(r.Divergences) != 0 {
+		t.Errorf("got divergences %+v, want none", r.Divergences)
+	}
+	if r.Actions != len(g.Actions) || r.Entries != len(g.Log) {
+		t.Errorf("got %d actions and %d entries, want %d and %d", r.Actions, r.Entries, len(g.Actions), len(g.Log))
+	}
+}
+
+func TestAuditOfTamperedGame(t *testing.T) {
+	client, c := newTestClient(), testContext()
+	g := newTestGame(t, 3)
+	playTurns(t, client, g, 4)
+
+	p := g.Players()[0]
+	p.Score += 3
+	w := g.ActiveWards()[0]
+	w.Bosses[p.ID()]++
+
+	r, err := client.Audit(c, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Consistent {
+		t.Fatal("tampered game is consistent")
+	}
+
+	fields := make(map[string]*AuditDivergence)
+	for _, d := range r.Divergences {
+		fields[d.Field] = d
+	}
+	if d := fields[auditScore]; d == nil || d.PlayerID != p.ID() || d.Recorded != d.Derived+3 {
+		t.Errorf("got score divergence %+v, want the tampered score of player %d", d, p.ID())
+	}
+	if d := fields[auditBosses]; d == nil || d.WardID != int(w.ID) || d.Recorded != d.Derived+1 {
+		t.Errorf("got bosses divergence %+v, want the tampered bosses in ward %d", d, w.ID)
+	}
+}
+
+func TestAuditOfIllegalAction(t *testing.T) {
+	client, c := newTestClient(), testContext()
+	g := newTestGame(t, 3)
+	playTurns(t, client, g, 1)
+
+	// A player may place at most two pieces a turn.
+	m := turnMoves(g)[0]
+	m.Bosses = 3
+	g.Actions = append(g.Actions, m.String())
+
+	r, err := client.Audit(c, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Consistent || len(r.Divergences) != 1 || r.Divergences[0].Field != auditAction || r.Divergences[0].Action != m.String() {
+		t.Errorf("got divergences %+v, want the illegal action %q", r.Divergences, m)
+	}
+}
+
+func TestAuditReportsAdminEdits(t *testing.T) {
+	client, c := newTestClient(), testContext()
+	g := newTestGame(t, 3)
+	playTurns(t, client, g, 1)
+
+	p := g.Players()[0]
+	edits := []*AdminEdit{{Target: adminPlayerTarget, ID: p.ID(), Values: json.RawMessage(fmt.Sprintf(`{"Score": %d}`, p.Score+2))}}
+	_, err := g.adminEdits(testAdmin(), "lost points", edits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bs, err := json.Marshal(edits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newMove("admin-edits")
+	m.Year = g.Year()
+	m.Params = url.Values{"reason": {"lost points"}, "edits": {string(bs)}}
+	g.recordMove(m)
+
+	r, err := client.Audit(c, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Divergences) != 1 {
+		t.Fatalf("got divergences %+v, want only the admin edit", r.Divergences)
+	}
+	d := r.Divergences[0]
+	if d.Field != auditAdminEdit || d.EntryIndex != len(g.Log)-1 || d.PlayerID != p.ID() || d.Reason != "lost points" {
+		t.Errorf("got divergence %+v, want the admin edit of player %d", d, p.ID())
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 func init() {
 	gob.RegisterName("*game.awardChipsEntry", new(awardChipsEntry))
+	registerEntry("awardChips", new(awardChipsEntry))
 }
 
 func (g *Game) startAwardChipsPhase(c *gin.Context) {
@@ -54,6 +55,10 @@ func (g *Game) newAwardChipsEntry() (e *awardChipsEntry) {
 }
 
 func (e *awardChipsEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
+	if g.language() != defaultLanguage {
+		return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+	}
+
 	ts := restful.TemplatesFrom(c)
 	buf := new(bytes.Buffer)
 	tmpl := ts["tammany/award_chips_entry"]
@@ -67,6 +72,29 @@ func (e *awardChipsEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.
 	return restful.HTML(buf.String())
 }
 
+func (e *awardChipsEntry) Text(g *Game) string {
+	var ss []string
+	for _, p := range g.Players() {
+		if cs := e.ChipWinners[p.ID()]; cs.Count() > 0 {
+			ss = append(ss, g.T("%s received %s favor chips", g.NameFor(p), g.chipsText(cs)))
+		}
+	}
+	if len(ss) == 0 {
+		return g.T("No favor chips were awarded.")
+	}
+	return g.toSentence(ss) + "."
+}
+
+func (e *awardChipsEntry) Event() *Event {
+	ev := e.event(e)
+	for pid, cs := range e.ChipWinners {
+		if counts := nationalCounts(cs); counts != nil {
+			ev.player(pid).Chips = counts
+		}
+	}
+	return ev
+}
+
 func (g *Game) awardChipsFor(n nationality) (winners Players) {
 	winners = g.chipWinners(n)
 	for _, player := range winners {
@@ -7,7 +7,6 @@ import (
 	"github.com/SlothNinja/game"
 	"github.com/SlothNinja/log"
 	"github.com/SlothNinja/restful"
-	"github.com/SlothNinja/sn"
 	"github.com/SlothNinja/user"
 	"github.com/gin-gonic/gin"
 )
@@ -45,12 +44,12 @@ func (g *Game) validateBid(c *gin.Context, cu *user.User) error {
 	defer log.Debugf(msgExit)
 
 	if !g.IsCurrentPlayer(cu) {
-		return sn.NewVError("Only the current player can place a bid.")
+		return g.verror(ErrNotCurrentPlayer, "Only the current player can place a bid.")
 	}
 
 	cp := g.CurrentPlayerFor(cu)
 	if cp.PerformedAction {
-		return sn.NewVError("You have already performed an action.")
+		return g.verror(ErrAlreadyActed, "You have already performed an action.")
 	}
 
 	for _, n := range g.Nationalities() {
@@ -63,12 +62,12 @@ func (g *Game) validateBid(c *gin.Context, cu *user.User) error {
 
 		switch {
 		case cp.PlayedChips[n] > 0 && g.CurrentWard().Immigrants[n] <= 0:
-			return sn.NewVError("You played %s favour chips, but there are no %s immigrants in ward %d",
+			return g.verror(ErrNoImmigrant, "You played %s favour chips, but there are no %s immigrants in ward %d",
 				n, n, g.CurrentWardID)
 		case cp.PlayedChips[n] < 0:
-			return sn.NewVError("Invalid value received for played %s chips.", n)
+			return g.verror(ErrInvalidValue, "Invalid value received for played %s chips.", n)
 		case cp.PlayedChips[n] > cp.Chips[n]:
-			return sn.NewVError("You played more %s chips, than you have.", n)
+			return g.verror(ErrInsufficientChips, "You played more %s chips, than you have.", n)
 		}
 	}
 	return nil
@@ -3,7 +3,6 @@ package tammany
 import (
 	"github.com/SlothNinja/game"
 	"github.com/SlothNinja/log"
-	"github.com/SlothNinja/sn"
 	"github.com/SlothNinja/user"
 	"github.com/gin-gonic/gin"
 )
@@ -24,9 +23,9 @@ func (g *Game) cancelFinish(c *gin.Context, cu *user.User) (string, game.ActionT
 func (g *Game) validateCancelFinish(c *gin.Context, cu *user.User) error {
 	switch {
 	case !g.IsCurrentPlayer(cu):
-		return sn.NewVError("Only the current player can take this action.")
+		return g.verror(ErrNotCurrentPlayer, "Only the current player can take this action.")
 	case !g.inActionPhase():
-		return sn.NewVError("Wrong phase for performing this action.")
+		return g.verror(ErrWrongPhase, "Wrong phase for performing this action.")
 	default:
 		return nil
 	}
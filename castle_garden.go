@@ -2,7 +2,6 @@ package tammany
 
 import (
 	"encoding/gob"
-	"fmt"
 	"html/template"
 
 	"github.com/SlothNinja/restful"
@@ -12,6 +11,7 @@ import (
 
 func init() {
 	gob.RegisterName("*game.castleGardenEntry", new(castleGardenEntry))
+	registerEntry("castleGarden", new(castleGardenEntry))
 }
 
 func defaultBag() Nationals {
@@ -21,7 +21,7 @@ func defaultBag() Nationals {
 func (g *Game) fillGardenFor(n int) (filled bool) {
 	if g.CastleGarden.empty() {
 		for i := 0; i < n+2; i++ {
-			g.CastleGarden[g.Bag.draw()]++
+			g.CastleGarden[g.drawFrom(g.Bag)]++
 		}
 		filled = true
 	}
@@ -64,16 +64,19 @@ func (g *Game) newCastleGardenEntry(p *Player) *castleGardenEntry {
 }
 
 func (e *castleGardenEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *castleGardenEntry) Text(g *Game) string {
 	n := g.NameByPID(e.PlayerID)
 	if !e.Filled {
-		return restful.HTML("%s placed no immigrants in the Castle Garden.", n)
-	}
-	segments := []string{}
-	for nationality := range defaultNationals() {
-		count := e.Immigrants[nationality]
-		if count != 0 {
-			segments = append(segments, fmt.Sprintf("%d %s", count, nationality))
-		}
+		return g.T("%s placed no immigrants in the Castle Garden.", n)
 	}
-	return restful.HTML("%s placed %s immigrants in the Castle Garden.", n, restful.ToSentence(segments))
+	return g.T("%s placed %s immigrants in the Castle Garden.", n, g.chipsText(Chips(e.Immigrants)))
+}
+
+func (e *castleGardenEntry) Event() *Event {
+	ev := e.event(e)
+	ev.Immigrants = nationalCounts(e.Immigrants)
+	return ev
 }
@@ -0,0 +1,294 @@
+// Command tammany plays Tammany Hall games from a terminal using the JSON API of the game server.
+//
+// Usage:
+//
+//	tammany [flags] games [status]                       list your games (default status: running)
+//	tammany [flags] show <game>                          show the board of a game
+//	tammany [flags] place <game> <ward> <bosses> [nat]   place bosses and an immigrant in a ward
+//	tammany [flags] slander <game> <ward> <nat> <player> slander a player in a ward using a favor chip
+//	tammany [flags] bid <game> <ward> [nat:count...]     bid favor chips in the ward election
+//	tammany [flags] assign <game> <office> <player>      assign an office to a player
+//	tammany [flags] finish [-confirm] <game>             finish your turn
+//	tammany [flags] move <game> <notation...>            perform any move given in move notation
+//
+// Wards may be given as 6 or W6, players as 3 or P3, and nationalities as Irish, Eng, Ger, or Ita.
+// Requests are authenticated by the session cookie of a logged in browser,
+// given by the -cookie flag or the TAMMANY_COOKIE environment variable.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type gameSummary struct {
+	ID             int64     `json:"id"`
+	Title          string    `json:"title"`
+	Status         string    `json:"status"`
+	Year           int       `json:"year"`
+	Phase          string    `json:"phase"`
+	Players        []string  `json:"players"`
+	CurrentPlayers []string  `json:"currentPlayers"`
+	YourTurn       bool      `json:"yourTurn"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+type moveResult struct {
+	Text    string   `json:"text"`
+	Warning string   `json:"warning"`
+	Notices []string `json:"notices"`
+	Error   string   `json:"error"`
+}
+
+// client makes requests of the JSON API of the game server.
+type client struct {
+	server string
+	cookie string
+	http   *http.Client
+}
+
+func main() {
+	fs := flag.NewFlagSet("tammany", flag.ExitOnError)
+	server := fs.String("server", os.Getenv("TAMMANY_SERVER"), "`url` of the game server, including any route prefix")
+	cookie := fs.String("cookie", os.Getenv("TAMMANY_COOKIE"), "session `cookie` of a logged in browser")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: tammany [flags] games|show|place|slander|bid|assign|finish|move [args]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(os.Args[1:])
+
+	if *server == "" || fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cl := &client{
+		server: strings.TrimRight(*server, "/"),
+		cookie: *cookie,
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}
+
+	err := cl.run(os.Stdout, fs.Arg(0), fs.Args()[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tammany:", err)
+		os.Exit(1)
+	}
+}
+
+func (cl *client) run(w io.Writer, cmd string, args []string) error {
+	switch cmd {
+	case "games":
+		status := "running"
+		if len(args) > 0 {
+			status = args[0]
+		}
+		return cl.games(w, status)
+	case "show":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: show <game>")
+		}
+		return cl.board(w, args[0])
+	case "finish":
+		fs := flag.NewFlagSet("finish", flag.ContinueOnError)
+		confirm := fs.Bool("confirm", false, "finish without using your office")
+		err := fs.Parse(args)
+		if err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: finish [-confirm] <game>")
+		}
+		return cl.move(w, fs.Arg(0), "FINISH", *confirm)
+	default:
+		if len(args) == 0 {
+			return fmt.Errorf("unknown command %q", cmd)
+		}
+		notation, err := notation(cmd, args[1:])
+		if err != nil {
+			return err
+		}
+		return cl.move(w, args[0], notation, false)
+	}
+}
+
+// notation provides the move notation of a move command.
+func notation(cmd string, args []string) (string, error) {
+	switch cmd {
+	case "place":
+		if len(args) < 2 || len(args) > 3 {
+			return "", fmt.Errorf("usage: place <game> <ward> <bosses> [nationality]")
+		}
+		pieces := "B" + args[1]
+		if len(args) == 3 {
+			pieces += "+" + args[2]
+		}
+		return pieces + "@" + wardArg(args[0]), nil
+	case "slander":
+		if len(args) != 3 {
+			return "", fmt.Errorf("usage: slander <game> <ward> <nationality> <player>")
+		}
+		return fmt.Sprintf("SL %s %s -> %s", wardArg(args[0]), args[1], playerArg(args[2])), nil
+	case "bid":
+		if len(args) < 1 {
+			return "", fmt.Errorf("usage: bid <game> <ward> [nationality:count...]")
+		}
+		return strings.Join(append([]string{"BID", wardArg(args[0])}, args[1:]...), " "), nil
+	case "assign":
+		if len(args) != 2 {
+			return "", fmt.Errorf("usage: assign <game> <office> <player>")
+		}
+		return fmt.Sprintf("ASSIGN %s %s", args[0], playerArg(args[1])), nil
+	case "move":
+		if len(args) == 0 {
+			return "", fmt.Errorf("usage: move <game> <notation...>")
+		}
+		return strings.Join(args, " "), nil
+	default:
+		return "", fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+func wardArg(s string) string {
+	return prefixed("W", s)
+}
+
+func playerArg(s string) string {
+	return prefixed("P", s)
+}
+
+// prefixed prefixes s by p, unless s is already prefixed.
+func prefixed(p, s string) string {
+	if strings.HasPrefix(strings.ToUpper(s), p) {
+		return s
+	}
+	return p + s
+}
+
+func (cl *client) games(w io.Writer, status string) error {
+	var resp struct {
+		Games []*gameSummary `json:"games"`
+	}
+	err := cl.do(http.MethodGet, "/api/games/"+url.PathEscape(status), nil, &resp)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Games) == 0 {
+		fmt.Fprintf(w, "You have no %s games.\n", status)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTurn\tYear\tPhase\tTitle\tCurrent Players\tUpdated")
+	for _, g := range resp.Games {
+		turn := ""
+		if g.YourTurn {
+			turn = "yours"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%s\t%s\t%s\t%s\n", g.ID, turn, g.Year, g.Phase, g.Title,
+			strings.Join(g.CurrentPlayers, ", "), g.UpdatedAt.Local().Format("Jan 2 15:04"))
+	}
+	return tw.Flush()
+}
+
+func (cl *client) board(w io.Writer, id string) error {
+	bs, err := cl.request(http.MethodGet, "/api/game/"+url.PathEscape(id)+"/board.txt", nil)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bs)
+	return err
+}
+
+func (cl *client) move(w io.Writer, id, notation string, confirm bool) error {
+	body := map[string]interface{}{"move": notation, "confirm": confirm}
+	r := new(moveResult)
+	err := cl.do(http.MethodPost, "/api/game/"+url.PathEscape(id)+"/move", body, r)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(w, r.Text)
+	for _, n := range r.Notices {
+		fmt.Fprintln(w, n)
+	}
+	if r.Warning != "" {
+		fmt.Fprintln(w, "Warning:", r.Warning)
+	}
+	return nil
+}
+
+// do makes a request of the API, decoding the JSON response into v.
+func (cl *client) do(method, path string, body, v interface{}) error {
+	bs, err := cl.request(method, path, body)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(bs, v)
+	if err != nil {
+		return fmt.Errorf("unexpected response to %s %s; is -server correct and -cookie current? %v", method, path, err)
+	}
+	return nil
+}
+
+// request makes a request of the API, providing the body of the response.
+// The body of the request, if any, is sent as JSON.
+func (cl *client) request(method, path string, body interface{}) ([]byte, error) {
+	var r io.Reader
+	if body != nil {
+		bs, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(bs)
+	}
+
+	req, err := http.NewRequest(method, cl.server+path, r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if cl.cookie != "" {
+		req.Header.Set("Cookie", cl.cookie)
+	}
+
+	resp, err := cl.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+			Code  string `json:"code"`
+		}
+		if json.Unmarshal(bs, &e) == nil && e.Error != "" {
+			if e.Code != "" {
+				return nil, fmt.Errorf("%s [%s]", strings.TrimSpace(e.Error), e.Code)
+			}
+			return nil, fmt.Errorf("%s", e.Error)
+		}
+		return nil, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	return bs, nil
+}
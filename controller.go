@@ -3,16 +3,15 @@ package tammany
 import (
 	"errors"
 	"fmt"
+	"html/template"
 	"net/http"
 	"strconv"
 
 	"cloud.google.com/go/datastore"
-	"github.com/SlothNinja/codec"
 	"github.com/SlothNinja/color"
 	"github.com/SlothNinja/contest"
 	"github.com/SlothNinja/game"
 	"github.com/SlothNinja/log"
-	"github.com/SlothNinja/mlog"
 	"github.com/SlothNinja/restful"
 	"github.com/SlothNinja/sn"
 	gtype "github.com/SlothNinja/type"
@@ -92,17 +91,21 @@ func (g *Game) update(c *gin.Context, cu *user.User) (string, game.ActionType, e
 	case "cancel-finish":
 		return g.cancelFinish(c, cu)
 	case "game-state":
-		return g.adminState(c)
+		return g.adminState(c, cu)
 	case "player":
-		return g.adminPlayer(c)
+		return g.adminPlayer(c, cu)
 	case "ward":
-		return g.adminWard(c)
+		return g.adminWard(c, cu)
 	case "castle-garden":
-		return g.adminCastleGarden(c)
+		return g.adminCastleGarden(c, cu)
 	case "immigrant-bag":
-		return g.adminImmigrantBag(c)
+		return g.adminImmigrantBag(c, cu)
+	case "admin-edits":
+		return g.adminEditsForm(c, cu)
+	case "rollback-admin-edit":
+		return g.adminRollback(c, cu)
 	default:
-		return "tammany/flash_notice", game.None, sn.NewVError("%v is not a valid action.", a)
+		return "tammany/flash_notice", game.None, g.verror(ErrInvalidAction, "%v is not a valid action.", a)
 	}
 }
 
@@ -124,7 +127,7 @@ func (client *Client) show(prefix string) gin.HandlerFunc {
 			return
 		}
 
-		ml, err := client.MLog.Get(c, id)
+		ml, err := client.Store.GetMLog(c, id)
 		if err != nil {
 			client.Log.Errorf(err.Error())
 			return
@@ -135,17 +138,40 @@ func (client *Client) show(prefix string) gin.HandlerFunc {
 			client.Log.Debugf(err.Error())
 		}
 
+		g := gameFrom(c)
+		var (
+			delayed    bool
+			breakdown  template.HTML
+			projection *Projection
+			whatIf     *WhatIf
+		)
+		switch {
+		case g == nil:
+		case g.Status == game.Completed:
+			breakdown = g.ScoreBreakdownHTML()
+		case g.Status == game.Running:
+			delayed = g.delayedFor(cu)
+			projection = g.Projection()
+			if cp, err := g.validateWhatIf(cu); err == nil {
+				whatIf = g.WhatIf(cp)
+			}
+		}
+
 		c.HTML(http.StatusOK, prefix+"/show", gin.H{
-			"Context":    c,
-			"VersionID":  sn.VersionID(),
-			"CUser":      cu,
-			"Game":       gameFrom(c),
-			"IsAdmin":    cu.IsAdmin(),
-			"Admin":      game.AdminFrom(c),
-			"MessageLog": ml,
-			"ColorMap":   color.MapFrom(c),
-			"Notices":    restful.NoticesFrom(c),
-			"Errors":     restful.ErrorsFrom(c),
+			"Context":        c,
+			"VersionID":      sn.VersionID(),
+			"CUser":          cu,
+			"Game":           g,
+			"IsAdmin":        cu.IsAdmin(),
+			"Admin":          game.AdminFrom(c),
+			"MessageLog":     ml,
+			"ColorMap":       color.MapFrom(c),
+			"ScoreBreakdown": breakdown,
+			"Projection":     projection,
+			"WhatIf":         whatIf,
+			"Delayed":        delayed,
+			"Notices":        restful.NoticesFrom(c),
+			"Errors":         restful.ErrorsFrom(c),
 		})
 	}
 }
@@ -167,7 +193,7 @@ func (client *Client) addMessage(prefix string) gin.HandlerFunc {
 			return
 		}
 
-		ml, err := client.MLog.Get(c, id)
+		ml, err := client.Store.GetMLog(c, id)
 		if err != nil {
 			client.Log.Errorf(err.Error())
 			return
@@ -175,7 +201,7 @@ func (client *Client) addMessage(prefix string) gin.HandlerFunc {
 
 		m := ml.AddMessage(cu, c.PostForm("message"))
 
-		_, err = client.MLog.Put(c, id, ml)
+		err = client.Store.PutMLog(c, ml)
 		if err != nil {
 			log.Errorf(err.Error())
 			return
@@ -209,8 +235,12 @@ func (client *Client) update(prefix string) gin.HandlerFunc {
 			return
 		}
 		template, actionType, err := g.update(c, cu)
+		if err == nil && (actionType == game.Cache || actionType == game.Save) {
+			g.recordUpdate(c, cu)
+		}
+
 		switch {
-		case err != nil && sn.IsVError(err):
+		case err != nil && isValidationError(err):
 			restful.AddErrorf(c, "%v", err)
 			withJSON(c, g)
 		case err != nil:
@@ -255,62 +285,48 @@ func (client *Client) update(prefix string) gin.HandlerFunc {
 }
 
 func (client *Client) save(c *gin.Context, g *Game, cu *user.User) error {
-	_, err := client.DS.RunInTransaction(c, func(tx *datastore.Transaction) error {
-		oldG := New(c, g.ID())
-		err := tx.Get(oldG.Key, oldG.Header)
-		if err != nil {
-			return err
-		}
-
-		if oldG.UpdatedAt != g.UpdatedAt {
-			return fmt.Errorf("Game state changed unexpectantly.  Try again.")
-		}
-
-		err = g.encode(c)
-		if err != nil {
-			return err
-		}
-
-		_, err = tx.Put(g.Key, g.Header)
-		if err != nil {
-			return err
-		}
-
-		client.Cache.Delete(g.UndoKey(cu))
-		return nil
-	})
-	return err
+	return client.saveWith(c, g, cu, nil, nil)
 }
 
 func (client *Client) saveWith(c *gin.Context, g *Game, cu *user.User, ks []*datastore.Key, es []interface{}) error {
-	_, err := client.DS.RunInTransaction(c, func(tx *datastore.Transaction) error {
-		oldG := New(c, g.ID())
-		err := tx.Get(oldG.Key, oldG.Header)
-		if err != nil {
-			return err
-		}
-
-		if oldG.UpdatedAt != g.UpdatedAt {
-			return fmt.Errorf("Game state changed unexpectantly.  Try again.")
-		}
+	err := g.encode(c)
+	if err != nil {
+		return err
+	}
 
-		err = g.encode(c)
-		if err != nil {
-			return err
-		}
+	err = client.Store.Put(c, g, ks, es)
+	if err != nil {
+		return err
+	}
 
-		ks = append(ks, g.Key)
-		es = append(es, g.Header)
+	client.Cache.Delete(g.UndoKey(cu))
+	client.putSnapshots(c, g)
+	return nil
+}
 
-		_, err = tx.PutMulti(ks, es)
+// putSnapshots saves the snapshots taken since the game was saved, from which the game may be replayed or rewound.
+// Once the game ended, only its first snapshot is kept, from which the game may still be replayed.
+// Failing to save the snapshots does not fail the save of the game.
+func (client *Client) putSnapshots(c *gin.Context, g *Game) {
+	for _, snap := range g.snapshots {
+		err := client.Store.PutSnapshot(c, g, snap)
 		if err != nil {
-			return err
+			client.Log.Warningf("unable to save snapshot of game %d: %v", g.ID(), err)
 		}
+	}
+	g.snapshots = nil
+
+	if g.Status != game.Completed {
+		return
+	}
 
-		client.Cache.Delete(g.UndoKey(cu))
-		return nil
-	})
-	return err
+	snaps, err := client.Store.Snapshots(c, g)
+	if err == nil && len(snaps) > 0 {
+		err = client.Store.DeleteSnapshots(c, g, snaps[0].LogLength)
+	}
+	if err != nil {
+		client.Log.Warningf("unable to prune snapshots of game %d: %v", g.ID(), err)
+	}
 }
 
 func (g *Game) encode(c *gin.Context) (err error) {
@@ -318,7 +334,7 @@ func (g *Game) encode(c *gin.Context) (err error) {
 	defer log.Debugf(msgExit)
 
 	var encoded []byte
-	if encoded, err = codec.Encode(g.State); err != nil {
+	if encoded, err = encodeState(g.State); err != nil {
 		return
 	}
 	g.SavedState = encoded
@@ -489,22 +505,7 @@ func (client *Client) create(prefix string) (f gin.HandlerFunc) {
 			return
 		}
 
-		ks, err := client.DS.AllocateIDs(c, []*datastore.Key{g.Key})
-		if err != nil {
-			client.Log.Errorf(err.Error())
-			c.Redirect(http.StatusSeeOther, recruitingPath(prefix))
-			return
-		}
-
-		k := ks[0]
-
-		_, err = client.DS.RunInTransaction(c, func(tx *datastore.Transaction) error {
-			m := mlog.New(k.ID)
-			ks := []*datastore.Key{m.Key, k}
-			es := []interface{}{m, g.Header}
-			_, err := tx.PutMulti(ks, es)
-			return err
-		})
+		err = client.Store.Create(c, g, nil)
 		if err != nil {
 			client.Log.Errorf(err.Error())
 			c.Redirect(http.StatusSeeOther, recruitingPath(prefix))
@@ -553,7 +554,7 @@ func (client *Client) accept(prefix string) gin.HandlerFunc {
 		}
 
 		if start {
-			err = g.SendTurnNotificationsTo(c, g.CurrentPlayerers()...)
+			err = client.sendTurnNotifications(c, g, g.CurrentPlayerers()...)
 			if err != nil {
 				client.Log.Warningf(err.Error())
 			}
@@ -602,6 +603,12 @@ func (client *Client) fetch(c *gin.Context) {
 	client.Log.Debugf(msgEnter)
 	defer client.Log.Debugf(msgExit)
 
+	defer func() {
+		if g := gameFrom(c); g != nil {
+			g.lang = client.languageFor(c)
+		}
+	}()
+
 	// create Gamer
 	id, err := strconv.ParseInt(c.Param("hid"), 10, 64)
 	if err != nil {
@@ -675,7 +682,7 @@ func (client *Client) dsGet(c *gin.Context, g *Game) error {
 	client.Log.Debugf(msgEnter)
 	defer client.Log.Debugf(msgExit)
 
-	err := client.DS.Get(c, g.Key, g.Header)
+	err := client.Store.Get(c, g)
 	if err != nil {
 		client.Log.Debugf("err: %v", err)
 		restful.AddErrorf(c, err.Error())
@@ -683,11 +690,10 @@ func (client *Client) dsGet(c *gin.Context, g *Game) error {
 	}
 
 	if g == nil {
-		return fmt.Errorf("Unable to get game for id: %v", g.ID)
+		return fmt.Errorf("Unable to get game for id: %v", g.ID())
 	}
 
-	s := newState()
-	err = codec.Decode(&s, g.SavedState)
+	s, _, err := decodeState(g.SavedState)
 	if err != nil {
 		client.Log.Debugf("err: %v", err)
 		restful.AddErrorf(c, err.Error())
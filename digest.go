@@ -0,0 +1,185 @@
+package tammany
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SlothNinja/game"
+	"github.com/gin-gonic/gin"
+)
+
+// digestPeriod provides the period summarized by a digest sent to a user not previously sent a digest.
+const digestPeriod = 24 * time.Hour
+
+type digestFailure struct {
+	UserID int64  `json:"userId"`
+	Error  string `json:"error"`
+}
+
+type digestSummary struct {
+	Users  int             `json:"users"`
+	Sent   int             `json:"sent"`
+	Failed []digestFailure `json:"failed"`
+}
+
+// digestGame provides a game awaiting the action of a user, and the entries of its log since the user's previous digest.
+type digestGame struct {
+	*Game
+	player  *Player
+	entries GameLog
+	omitted int
+}
+
+// sendDigests notifies each user preferring a daily digest the Tammany Hall games awaiting their action.
+// The handler is intended to be run daily by the scheduler, which identifies itself with the X-Appengine-Cron header,
+// but may also be run by an admin.
+func (client *Client) sendDigests(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		if c.GetHeader("X-Appengine-Cron") != "true" {
+			cu, err := client.User.Current(c)
+			if err != nil || !cu.IsAdmin() {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Only the scheduler or an admin may send digests."})
+				return
+			}
+		}
+
+		ps, err := client.Prefs.DigestPrefs(c)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		summary := digestSummary{Failed: []digestFailure{}}
+		now := time.Now()
+		for _, p := range ps {
+			summary.Users++
+			sent, err := client.sendDigest(c, prefix, p, now)
+			if err != nil {
+				client.Log.Warningf("unable to send digest to user %d: %v", p.UserID(), err)
+				summary.Failed = append(summary.Failed, digestFailure{UserID: p.UserID(), Error: err.Error()})
+				continue
+			}
+			if sent {
+				summary.Sent++
+			}
+		}
+		c.JSON(http.StatusOK, summary)
+	}
+}
+
+// sendDigest notifies the user having preferences p, through the channel and in the language preferred by the user,
+// of the games awaiting their action, if any, and records the digest as sent at now.
+func (client *Client) sendDigest(c *gin.Context, prefix string, p *Prefs, now time.Time) (bool, error) {
+	since := p.LastDigestAt
+	if since.IsZero() {
+		since = now.Add(-digestPeriod)
+	}
+
+	dgs, err := client.digestGamesFor(c, p.UserID(), since)
+	if err != nil {
+		return false, err
+	}
+
+	if len(dgs) > 0 {
+		first := dgs[0]
+		err = client.deliver(c, p, first.EmailFor(first.player), first.NameFor(first.player), func(lang string) (*Notification, error) {
+			return digestNotification(c, prefix, lang, dgs)
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	p.LastDigestAt = now
+	return len(dgs) > 0, client.Prefs.PutPrefs(c, p)
+}
+
+// digestGamesFor provides the running games awaiting the action of the user, most recently updated first.
+func (client *Client) digestGamesFor(c *gin.Context, uid int64, since time.Time) ([]*digestGame, error) {
+	gs, err := client.Store.ListByUser(c, game.Running, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	var dgs []*digestGame
+	for _, g := range gs {
+		s, _, err := decodeState(g.SavedState)
+		if err != nil {
+			client.Log.Warningf("unable to decode state of game %d: %v", g.ID(), err)
+			continue
+		}
+		g.State = s
+
+		err = client.init(c, g)
+		if err != nil {
+			client.Log.Warningf("unable to initialize game %d: %v", g.ID(), err)
+			continue
+		}
+
+		p := g.playerByUserID(uid)
+		if p == nil || !g.isCurrent(p) {
+			continue
+		}
+
+		dg := &digestGame{Game: g, player: p}
+		for _, e := range g.Log {
+			if e.CreatedAt().After(since) {
+				dg.entries = append(dg.entries, e)
+			}
+		}
+		if len(dg.entries) > maxSummaryEntries {
+			dg.omitted, dg.entries = len(dg.entries)-maxSummaryEntries, dg.entries[len(dg.entries)-maxSummaryEntries:]
+		}
+		dgs = append(dgs, dg)
+	}
+	return dgs, nil
+}
+
+func (g *Game) isCurrent(p *Player) bool {
+	for _, cp := range g.CurrentPlayers() {
+		if cp.ID() == p.ID() {
+			return true
+		}
+	}
+	return false
+}
+
+// digestItem provides a game of a digest.
+type digestItem struct {
+	GameID  int64
+	Title   string
+	Link    string
+	Omitted int
+	Entries []string
+}
+
+// digestNotification provides the digest of the games in the language.
+func digestNotification(c *gin.Context, prefix, lang string, dgs []*digestGame) (*Notification, error) {
+	note := &Notification{Kind: digestNotice, Link: imageBase(c) + homePath}
+
+	items := make([]*digestItem, len(dgs))
+	for i, dg := range dgs {
+		g := dg.withLanguage(lang)
+		item := &digestItem{
+			GameID:  g.ID(),
+			Title:   g.Title,
+			Link:    imageBase(c) + showPath(prefix, fmt.Sprint(g.ID())),
+			Omitted: dg.omitted,
+		}
+		for _, e := range dg.entries {
+			item.Entries = append(item.Entries, e.Text(g))
+		}
+		items[i] = item
+	}
+
+	err := renderNotification(note, lang, digestNotice, struct {
+		Count int
+		Games []*digestItem
+	}{len(dgs), items})
+	return note, err
+}
@@ -0,0 +1,63 @@
+package tammany
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendDigestThroughChannel(t *testing.T) {
+	client, c := newTestClient(), testContext()
+	g := newTestGame(t, 3)
+	playTurns(t, client, g, 1)
+
+	var err error
+	g.SavedState, err = encodeState(g.State)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.Store.Create(c, g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bodies [][]byte
+	srv := testWebhook(t, http.StatusNoContent, &bodies)
+	defer srv.Close()
+
+	cp := g.CurrentPlayer()
+	prefs := newPrefs(g.UserIDS[cp.ID()])
+	prefs.Digest, prefs.Channel, prefs.WebhookURL, prefs.Language = true, discordChannel, srv.URL, "de"
+
+	now := time.Now()
+	sent, err := client.sendDigest(c, "tammany", prefs, now)
+	if err != nil || !sent {
+		t.Fatalf("got sent %v and error %v, want the digest sent", sent, err)
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("got %d posts, want 1", len(bodies))
+	}
+
+	var msg map[string]string
+	err = json.Unmarshal(bodies[0], &msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The entries of the log are in the language of the user.
+	entry := g.Log[len(g.Log)-1].Text(g.withLanguage("de"))
+	content := msg["content"]
+	if !strings.HasPrefix(content, "SlothNinja Games: 1 Tammany-Hall-Spiele warten auf deinen Zug") || !strings.Contains(content, entry) {
+		t.Errorf("got %q, want the German digest including %q", content, entry)
+	}
+
+	saved, err := client.Prefs.GetPrefs(c, prefs.UserID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !saved.LastDigestAt.Equal(now) {
+		t.Errorf("got last digest at %v, want %v", saved.LastDigestAt, now)
+	}
+}
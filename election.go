@@ -3,6 +3,7 @@ package tammany
 import (
 	"bytes"
 	"encoding/gob"
+	"fmt"
 	"html/template"
 
 	"github.com/SlothNinja/log"
@@ -13,7 +14,9 @@ import (
 
 func init() {
 	gob.RegisterName("*game.resolvedElectionEntry", new(resolvedElectionEntry))
+	registerEntry("resolvedElection", new(resolvedElectionEntry))
 	gob.RegisterName("*game.wonWardEntry", new(wonWardEntry))
+	registerEntry("wonWard", new(wonWardEntry))
 }
 
 func (g *Game) resolve(c *gin.Context, cu *user.User, w *Ward) (resolved bool) {
@@ -137,7 +140,17 @@ func (g *Game) newWonWardEntry(p *Player) *wonWardEntry {
 }
 
 func (e *wonWardEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	return restful.HTML("%s won the election in ward %d.", g.NameByPID(e.PlayerID), e.WardID)
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *wonWardEntry) Text(g *Game) string {
+	return g.T("%s won the election in ward %d.", g.NameByPID(e.PlayerID), e.WardID)
+}
+
+func (e *wonWardEntry) Event() *Event {
+	ev := e.event(e)
+	ev.WardID = int(e.WardID)
+	return ev
 }
 
 func (g *Game) startElectionIn(c *gin.Context, cu *user.User, w *Ward) (resolved bool) {
@@ -212,6 +225,10 @@ func (w *Ward) playableChipsFor(player *Player) (chips int) {
 }
 
 func (e *resolvedElectionEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
+	if g.language() != defaultLanguage {
+		return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+	}
+
 	ts := restful.TemplatesFrom(c)
 	buf := new(bytes.Buffer)
 	tmpl := ts["tammany/resolved_election_entry"]
@@ -224,3 +241,96 @@ func (e *resolvedElectionEntry) HTML(c *gin.Context, g *Game, cu *user.User) tem
 	}
 	return restful.HTML(buf.String())
 }
+
+func (e *resolvedElectionEntry) Text(g *Game) string {
+	var ss []string
+	for _, p := range g.Players() {
+		bosses := e.Bosses[p.ID()]
+		if bosses == 0 {
+			continue
+		}
+		s := g.T("%s had %d bosses", g.NameFor(p), bosses)
+		if bosses == 1 {
+			s = g.T("%s had 1 boss", g.NameFor(p))
+		}
+		if cs := e.PlayedChips[p.ID()]; cs.Count() > 0 {
+			s += g.T(" and played %s favor chips", g.chipsText(cs))
+		}
+		ss = append(ss, s)
+	}
+
+	var result string
+	switch {
+	case len(ss) == 0:
+		return g.T("No bosses contested the election in ward %d.", e.WardID)
+	case e.PlayerID == noPlayerID:
+		result = g.T("The election in ward %d was a tie.", e.WardID)
+	default:
+		result = g.T("%s won the election in ward %d.", g.NameByPID(e.PlayerID), e.WardID)
+	}
+	return fmt.Sprintf("%s %s.", result, g.toSentence(ss))
+}
+
+func (e *resolvedElectionEntry) Event() *Event {
+	ev := e.event(e)
+	ev.WardID = int(e.WardID)
+	ev.Contested = e.Contested
+	for pid, bosses := range e.Bosses {
+		if bosses > 0 {
+			ev.player(pid).Bosses = bosses
+		}
+	}
+	for pid, cs := range e.PlayedChips {
+		if counts := nationalCounts(cs); counts != nil {
+			ev.player(pid).Chips = counts
+		}
+	}
+	return ev
+}
+
+// sendElectionNotifications notifies the players, and followers wanting notice of elections, of the results of the
+// elections resolved by entries of the log following the first logLen entries.
+// Followers of a game having a spectator delay are not notified, lest the results precede the delayed view.
+func (client *Client) sendElectionNotifications(c *gin.Context, g *Game, logLen int) {
+	var es []*resolvedElectionEntry
+	for _, e := range g.Log[logLen:] {
+		if re, ok := e.(*resolvedElectionEntry); ok {
+			es = append(es, re)
+		}
+	}
+	if len(es) == 0 {
+		return
+	}
+
+	build := func(lang string) (*Notification, error) {
+		return g.electionNotification(c, lang, es)
+	}
+
+	for _, p := range g.Players() {
+		err := client.notify(c, g, p, build)
+		if err != nil {
+			client.Log.Warningf("unable to notify %s of election results in game %d: %v", g.NameFor(p), g.ID(), err)
+		}
+	}
+
+	if g.SpectatorDelay == 0 {
+		client.notifyFollowers(c, g, func(f *Follow) bool { return f.Elections }, build)
+	}
+}
+
+func (g *Game) electionNotification(c *gin.Context, lang string, es []*resolvedElectionEntry) (*Notification, error) {
+	lg := g.withLanguage(lang)
+	results := make([]string, len(es))
+	for i, e := range es {
+		results[i] = e.Text(lg)
+	}
+
+	note := g.newNotification(c, electionNotice)
+	err := renderNotification(note, lang, electionNotice, struct {
+		GameID  int64
+		Title   string
+		Link    string
+		Results []string
+	}{g.ID(), g.Title, note.Link, results})
+	return note, err
+}
@@ -0,0 +1,246 @@
+package tammany
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	ttemplate "text/template"
+)
+
+// defaultLanguage provides the language of notifications to users lacking a supported language preference.
+const defaultLanguage = "en"
+
+// languages provides the languages in which notifications are available.
+var languages = []string{"en", "de"}
+
+func supportedLanguage(lang string) bool {
+	for _, l := range languages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// emailTemplate renders the subject, plain text, and HTML parts of a notification.
+type emailTemplate struct {
+	subject *ttemplate.Template
+	text    *ttemplate.Template
+	html    *template.Template
+}
+
+func newEmailTemplate(name, subject, text, html string) *emailTemplate {
+	return &emailTemplate{
+		subject: ttemplate.Must(ttemplate.New(name + "_subject").Parse(subject)),
+		text:    ttemplate.Must(ttemplate.New(name + "_text").Parse(text)),
+		html:    template.Must(template.New(name + "_html").Parse(htmlLayout + html)),
+	}
+}
+
+// render renders the template into note.
+func (t *emailTemplate) render(note *Notification, data interface{}) error {
+	subject, text, html := new(strings.Builder), new(strings.Builder), new(bytes.Buffer)
+	for _, err := range []error{
+		t.subject.Execute(subject, data),
+		t.text.Execute(text, data),
+		t.html.Execute(html, data),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	note.Subject, note.Text, note.HTML = strings.TrimSpace(subject.String()), text.String(), html.String()
+	return nil
+}
+
+// renderNotification renders the named template in the language, or the default language if the language is unsupported.
+func renderNotification(note *Notification, lang, name string, data interface{}) error {
+	ts, ok := emailTemplates[lang]
+	if !ok {
+		ts = emailTemplates[defaultLanguage]
+	}
+	return ts[name].render(note, data)
+}
+
+// htmlLayout wraps the body template of each HTML part.
+const htmlLayout = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"></head>
+<body>{{template "body" .}}</body>
+</html>`
+
+var emailTemplates = map[string]map[string]*emailTemplate{
+	"en": {
+		turnNotice: newEmailTemplate("en_turn",
+			`SlothNinja Games: It's your turn in {{.Title}} ({{.GameID}})`,
+			`It's your turn in {{.Title}} ({{.GameID}}), Year {{.Year}}, {{.Phase}}.
+{{.Link}}
+{{if .Entries}}
+Since your last turn:
+{{if .Omitted}}  ({{.Omitted}} earlier entries omitted)
+{{end}}{{range .Entries}}  - {{.}}
+{{end}}{{end}}
+{{.Board}}`,
+			`{{define "body"}}<p>It's your turn in <a href="{{.Link}}">{{.Title}} ({{.GameID}})</a>, Year {{.Year}}, {{.Phase}}.</p>
+{{if .Entries}}<h3>Since your last turn</h3>
+{{if .Omitted}}<p>{{.Omitted}} earlier entries omitted.</p>{{end}}
+<ul>{{range .Entries}}<li>{{.}}</li>{{end}}</ul>{{end}}
+<pre>{{.Board}}</pre>{{end}}`),
+
+		electionNotice: newEmailTemplate("en_election",
+			`SlothNinja Games: Election results in {{.Title}} ({{.GameID}})`,
+			`Election results in {{.Title}} ({{.GameID}}):
+{{range .Results}}  - {{.}}
+{{end}}
+{{.Link}}
+`,
+			`{{define "body"}}<p>Election results in <a href="{{.Link}}">{{.Title}} ({{.GameID}})</a>:</p>
+<ul>{{range .Results}}<li>{{.}}</li>{{end}}</ul>{{end}}`),
+
+		endGameNotice: newEmailTemplate("en_end",
+			`SlothNinja Games: Tammany Hall #{{.GameID}} Has Ended`,
+			`{{.Title}} ({{.GameID}}) has ended.
+{{.Link}}
+
+Final standings:
+{{range .Standings}}  {{.Place}}. {{.Name}}: {{.Score}} points{{if .Winner}} (winner){{end}}
+{{end}}
+Victory points:
+{{range .Breakdowns}}  {{.Name}}: wards {{.VP.Wards}}, ward 14 bonus {{.VP.Ward14Bonus}}, mayor {{.VP.Mayor}}, chip majorities {{.VP.ChipMajorities}}, slander chips {{.VP.SlanderChips}}, total {{.VP.Total}}
+{{end}}{{if .RatingChanges}}
+Projected rating changes:
+{{range .RatingChanges}}  {{.Name}}: {{printf "%.f" .Before}} -> {{printf "%.f" .After}} ({{printf "%+.f" .Delta}})
+{{end}}{{end}}
+Congratulations to: {{.Winners}}.
+`,
+			`{{define "body"}}<p><a href="{{.Link}}">{{.Title}} ({{.GameID}})</a> has ended.</p>
+<h3>Final standings</h3>
+<table>
+<tr><th>Place</th><th>Player</th><th>Score</th></tr>
+{{range .Standings}}<tr><td>{{.Place}}</td><td>{{if .Winner}}<strong>{{.Name}}</strong>{{else}}{{.Name}}{{end}}</td><td>{{.Score}}</td></tr>
+{{end}}</table>
+<h3>Victory points</h3>
+<table>
+<tr><th>Player</th><th>Wards</th><th>Ward 14 Bonus</th><th>Mayor</th><th>Chip Majorities</th><th>Slander Chips</th><th>Total</th></tr>
+{{range .Breakdowns}}<tr><td>{{.Name}}</td><td>{{.VP.Wards}}</td><td>{{.VP.Ward14Bonus}}</td><td>{{.VP.Mayor}}</td><td>{{.VP.ChipMajorities}}</td><td>{{.VP.SlanderChips}}</td><td>{{.VP.Total}}</td></tr>
+{{end}}</table>
+{{if .RatingChanges}}<h3>Projected rating changes</h3>
+<table>
+<tr><th>Player</th><th>Before</th><th>After</th><th>Change</th></tr>
+{{range .RatingChanges}}<tr><td>{{.Name}}</td><td>{{printf "%.f" .Before}}</td><td>{{printf "%.f" .After}}</td><td>{{printf "%+.f" .Delta}}</td></tr>
+{{end}}</table>{{end}}
+<p>Congratulations to: {{.Winners}}.</p>{{end}}`),
+
+		rewindNotice: newEmailTemplate("en_rewind",
+			`SlothNinja Games: Tammany Hall #{{.GameID}} Was Rewound`,
+			`{{.Rewound}}
+Moves made after that entry were undone. It is now the turn of {{.Current}}.
+{{.Link}}
+`,
+			`{{define "body"}}<p>{{.Rewound}}</p>
+<p>Moves made after that entry were undone. It is now the turn of {{.Current}}.</p>
+<p><a href="{{.Link}}">{{.Title}} ({{.GameID}})</a></p>{{end}}`),
+
+		digestNotice: newEmailTemplate("en_digest",
+			`SlothNinja Games: {{.Count}} Tammany Hall games await your action`,
+			`{{.Count}} Tammany Hall games await your action.
+{{range .Games}}
+{{.Title}} ({{.GameID}})
+{{.Link}}
+{{if .Omitted}}  ({{.Omitted}} earlier entries omitted)
+{{end}}{{range .Entries}}  - {{.}}
+{{end}}{{end}}`,
+			`{{define "body"}}<p>{{.Count}} Tammany Hall games await your action.</p>
+{{range .Games}}<h3><a href="{{.Link}}">{{.Title}} ({{.GameID}})</a></h3>
+{{if .Omitted}}<p>{{.Omitted}} earlier entries omitted.</p>{{end}}
+{{if .Entries}}<ul>{{range .Entries}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{end}}{{end}}`),
+	},
+
+	"de": {
+		turnNotice: newEmailTemplate("de_turn",
+			`SlothNinja Games: Du bist am Zug in {{.Title}} ({{.GameID}})`,
+			`Du bist am Zug in {{.Title}} ({{.GameID}}), Jahr {{.Year}}, {{.Phase}}.
+{{.Link}}
+{{if .Entries}}
+Seit deinem letzten Zug:
+{{if .Omitted}}  ({{.Omitted}} frühere Einträge ausgelassen)
+{{end}}{{range .Entries}}  - {{.}}
+{{end}}{{end}}
+{{.Board}}`,
+			`{{define "body"}}<p>Du bist am Zug in <a href="{{.Link}}">{{.Title}} ({{.GameID}})</a>, Jahr {{.Year}}, {{.Phase}}.</p>
+{{if .Entries}}<h3>Seit deinem letzten Zug</h3>
+{{if .Omitted}}<p>{{.Omitted}} frühere Einträge ausgelassen.</p>{{end}}
+<ul>{{range .Entries}}<li>{{.}}</li>{{end}}</ul>{{end}}
+<pre>{{.Board}}</pre>{{end}}`),
+
+		electionNotice: newEmailTemplate("de_election",
+			`SlothNinja Games: Wahlergebnisse in {{.Title}} ({{.GameID}})`,
+			`Wahlergebnisse in {{.Title}} ({{.GameID}}):
+{{range .Results}}  - {{.}}
+{{end}}
+{{.Link}}
+`,
+			`{{define "body"}}<p>Wahlergebnisse in <a href="{{.Link}}">{{.Title}} ({{.GameID}})</a>:</p>
+<ul>{{range .Results}}<li>{{.}}</li>{{end}}</ul>{{end}}`),
+
+		endGameNotice: newEmailTemplate("de_end",
+			`SlothNinja Games: Tammany Hall #{{.GameID}} ist beendet`,
+			`{{.Title}} ({{.GameID}}) ist beendet.
+{{.Link}}
+
+Endstand:
+{{range .Standings}}  {{.Place}}. {{.Name}}: {{.Score}} Punkte{{if .Winner}} (Sieger){{end}}
+{{end}}
+Siegpunkte:
+{{range .Breakdowns}}  {{.Name}}: Bezirke {{.VP.Wards}}, Bonus Bezirk 14 {{.VP.Ward14Bonus}}, Bürgermeister {{.VP.Mayor}}, Chip-Mehrheiten {{.VP.ChipMajorities}}, Verleumdungschips {{.VP.SlanderChips}}, gesamt {{.VP.Total}}
+{{end}}{{if .RatingChanges}}
+Voraussichtliche Wertungsänderungen:
+{{range .RatingChanges}}  {{.Name}}: {{printf "%.f" .Before}} -> {{printf "%.f" .After}} ({{printf "%+.f" .Delta}})
+{{end}}{{end}}
+Herzlichen Glückwunsch an: {{.Winners}}.
+`,
+			`{{define "body"}}<p><a href="{{.Link}}">{{.Title}} ({{.GameID}})</a> ist beendet.</p>
+<h3>Endstand</h3>
+<table>
+<tr><th>Platz</th><th>Spieler</th><th>Punkte</th></tr>
+{{range .Standings}}<tr><td>{{.Place}}</td><td>{{if .Winner}}<strong>{{.Name}}</strong>{{else}}{{.Name}}{{end}}</td><td>{{.Score}}</td></tr>
+{{end}}</table>
+<h3>Siegpunkte</h3>
+<table>
+<tr><th>Spieler</th><th>Bezirke</th><th>Bonus Bezirk 14</th><th>Bürgermeister</th><th>Chip-Mehrheiten</th><th>Verleumdungschips</th><th>Gesamt</th></tr>
+{{range .Breakdowns}}<tr><td>{{.Name}}</td><td>{{.VP.Wards}}</td><td>{{.VP.Ward14Bonus}}</td><td>{{.VP.Mayor}}</td><td>{{.VP.ChipMajorities}}</td><td>{{.VP.SlanderChips}}</td><td>{{.VP.Total}}</td></tr>
+{{end}}</table>
+{{if .RatingChanges}}<h3>Voraussichtliche Wertungsänderungen</h3>
+<table>
+<tr><th>Spieler</th><th>Vorher</th><th>Nachher</th><th>Änderung</th></tr>
+{{range .RatingChanges}}<tr><td>{{.Name}}</td><td>{{printf "%.f" .Before}}</td><td>{{printf "%.f" .After}}</td><td>{{printf "%+.f" .Delta}}</td></tr>
+{{end}}</table>{{end}}
+<p>Herzlichen Glückwunsch an: {{.Winners}}.</p>{{end}}`),
+
+		rewindNotice: newEmailTemplate("de_rewind",
+			`SlothNinja Games: Tammany Hall #{{.GameID}} wurde zurückgesetzt`,
+			`{{.Rewound}}
+Die Züge nach diesem Eintrag wurden rückgängig gemacht. Jetzt ist {{.Current}} am Zug.
+{{.Link}}
+`,
+			`{{define "body"}}<p>{{.Rewound}}</p>
+<p>Die Züge nach diesem Eintrag wurden rückgängig gemacht. Jetzt ist {{.Current}} am Zug.</p>
+<p><a href="{{.Link}}">{{.Title}} ({{.GameID}})</a></p>{{end}}`),
+
+		digestNotice: newEmailTemplate("de_digest",
+			`SlothNinja Games: {{.Count}} Tammany-Hall-Spiele warten auf deinen Zug`,
+			`{{.Count}} Tammany-Hall-Spiele warten auf deinen Zug.
+{{range .Games}}
+{{.Title}} ({{.GameID}})
+{{.Link}}
+{{if .Omitted}}  ({{.Omitted}} frühere Einträge ausgelassen)
+{{end}}{{range .Entries}}  - {{.}}
+{{end}}{{end}}`,
+			`{{define "body"}}<p>{{.Count}} Tammany-Hall-Spiele warten auf deinen Zug.</p>
+{{range .Games}}<h3><a href="{{.Link}}">{{.Title}} ({{.GameID}})</a></h3>
+{{if .Omitted}}<p>{{.Omitted}} frühere Einträge ausgelassen.</p>{{end}}
+{{if .Entries}}<ul>{{range .Entries}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{end}}{{end}}`),
+	},
+}
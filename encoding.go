@@ -0,0 +1,287 @@
+package tammany
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/SlothNinja/codec"
+	"github.com/SlothNinja/color"
+)
+
+// stateSchemaVersion is the schema version of newly encoded game state.
+//
+// Changes to State or Player that alter their encoding must increment stateSchemaVersion and
+// register a migration in stateMigrations that upgrades state encoded with the prior version.
+const stateSchemaVersion = 1
+
+// savedState provides the versioned envelope of encoded game state.
+type savedState struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	State         json.RawMessage `json:"state"`
+}
+
+// stateMigration upgrades encoded game state from one schema version to the next.
+type stateMigration func([]byte) ([]byte, error)
+
+// stateMigrations maps a schema version to the migration that upgrades encoded game state to the next version.
+// Version 0 denotes the legacy gob encoding of State.
+var stateMigrations = map[int]stateMigration{
+	0: migrateGobState,
+}
+
+// encodeState encodes game state s using the current schema version.
+func encodeState(s *State) ([]byte, error) {
+	bs, err := json.Marshal(toJState(s))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(savedState{SchemaVersion: stateSchemaVersion, State: bs})
+}
+
+// decodeState decodes game state, upgrading it to the current schema version as necessary.
+// decodeState also returns the schema version with which the state was encoded.
+func decodeState(bs []byte) (*State, int, error) {
+	version, doc := stateVersion(bs)
+	if version > stateSchemaVersion {
+		return nil, version, fmt.Errorf("unsupported state schema version %d", version)
+	}
+
+	var err error
+	for v := version; v < stateSchemaVersion; v++ {
+		migrate, ok := stateMigrations[v]
+		if !ok {
+			return nil, version, fmt.Errorf("missing migration for state schema version %d", v)
+		}
+
+		doc, err = migrate(doc)
+		if err != nil {
+			return nil, version, fmt.Errorf("unable to migrate state from schema version %d: %w", v, err)
+		}
+	}
+
+	js := new(jState)
+	err = json.Unmarshal(doc, js)
+	if err != nil {
+		return nil, version, err
+	}
+	return js.toState(), version, nil
+}
+
+// stateVersion returns the schema version of the encoded state together with the state document.
+// Encoded state lacking a versioned envelope is presumed to be legacy gob encoded state.
+func stateVersion(bs []byte) (int, []byte) {
+	var saved savedState
+	err := json.Unmarshal(bs, &saved)
+	if err != nil || saved.SchemaVersion < 1 {
+		return 0, bs
+	}
+	return saved.SchemaVersion, saved.State
+}
+
+// migrateGobState upgrades legacy gob encoded state to schema version 1.
+func migrateGobState(bs []byte) ([]byte, error) {
+	s := newState()
+	err := codec.Decode(&s, bs)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(toJState(s))
+}
+
+type jState struct {
+	Players            []*jPlayer
+	Log                jGameLog
+	Wards              Wards
+	CastleGarden       Nationals
+	Bag                Nationals
+	CurrentWardID      wardID
+	SelectedWardID     wardID
+	MoveFromWardID     wardID
+	SelectedOffice     office
+	ImmigrantInTransit nationality
+	SlanderedPlayerID  int
+	SlanderNationality nationality
+	ConfirmedOffice    bool
+	Actions            []string      `json:",omitempty"`
+	Draws              []nationality `json:",omitempty"`
+	SpectatorDelay     int           `json:",omitempty"`
+}
+
+func toJState(s *State) *jState {
+	js := &jState{
+		Log:                jGameLog(s.Log),
+		Wards:              s.Wards,
+		CastleGarden:       s.CastleGarden,
+		Bag:                s.Bag,
+		CurrentWardID:      s.CurrentWardID,
+		SelectedWardID:     s.SelectedWardID,
+		MoveFromWardID:     s.MoveFromWardID,
+		SelectedOffice:     s.SelectedOffice,
+		ImmigrantInTransit: s.ImmigrantInTransit,
+		SlanderedPlayerID:  s.SlanderedPlayerID,
+		SlanderNationality: s.SlanderNationality,
+		ConfirmedOffice:    s.ConfirmedOffice,
+		Actions:            s.Actions,
+		Draws:              s.Draws,
+		SpectatorDelay:     s.SpectatorDelay,
+	}
+	for _, per := range s.Playerers {
+		js.Players = append(js.Players, toJPlayer(per.(*Player)))
+	}
+	return js
+}
+
+func (js *jState) toState() *State {
+	s := newState()
+	s.Log = GameLog(js.Log)
+	s.Wards = js.Wards
+	s.CastleGarden = js.CastleGarden
+	s.Bag = js.Bag
+	s.CurrentWardID = js.CurrentWardID
+	s.SelectedWardID = js.SelectedWardID
+	s.MoveFromWardID = js.MoveFromWardID
+	s.SelectedOffice = js.SelectedOffice
+	s.ImmigrantInTransit = js.ImmigrantInTransit
+	s.SlanderedPlayerID = js.SlanderedPlayerID
+	s.SlanderNationality = js.SlanderNationality
+	s.ConfirmedOffice = js.ConfirmedOffice
+	s.Actions = js.Actions
+	s.Draws = js.Draws
+	s.SpectatorDelay = js.SpectatorDelay
+	for _, jp := range js.Players {
+		s.Playerers = append(s.Playerers, jp.toPlayer())
+	}
+	return s
+}
+
+// jPlayer provides the encoding of a player.
+// game.Player implements json.Marshaler for clients of the JSON api, which omits fields necessary
+// to restore a player.  Hence, a player is encoded using this explicit representation.
+type jPlayer struct {
+	ID               int
+	PerformedAction  bool
+	Score            int
+	Passed           bool
+	ColorMap         color.Colors
+	Log              jGameLog
+	Chips            Chips
+	PlayedChips      Chips
+	Office           office
+	PlacedBosses     int
+	PlacedImmigrants int
+	LockedUp         int
+	Slandered        int
+	SlanderChips     slanderChips
+	Candidate        bool
+	HasBid           bool
+	UsedOffice       bool
+}
+
+func toJPlayer(p *Player) *jPlayer {
+	return &jPlayer{
+		ID:               p.ID(),
+		PerformedAction:  p.PerformedAction,
+		Score:            p.Score,
+		Passed:           p.Passed,
+		ColorMap:         p.ColorMap(),
+		Log:              jGameLog(p.Log),
+		Chips:            p.Chips,
+		PlayedChips:      p.PlayedChips,
+		Office:           p.Office,
+		PlacedBosses:     p.PlacedBosses,
+		PlacedImmigrants: p.PlacedImmigrants,
+		LockedUp:         p.LockedUp,
+		Slandered:        p.Slandered,
+		SlanderChips:     p.SlanderChips,
+		Candidate:        p.Candidate,
+		HasBid:           p.HasBid,
+		UsedOffice:       p.UsedOffice,
+	}
+}
+
+func (jp *jPlayer) toPlayer() *Player {
+	p := newPlayer()
+	p.SetID(jp.ID)
+	p.PerformedAction = jp.PerformedAction
+	p.Score = jp.Score
+	p.Passed = jp.Passed
+	p.SetColorMap(jp.ColorMap)
+	p.Log = GameLog(jp.Log)
+	p.Chips = jp.Chips
+	p.PlayedChips = jp.PlayedChips
+	p.Office = jp.Office
+	p.PlacedBosses = jp.PlacedBosses
+	p.PlacedImmigrants = jp.PlacedImmigrants
+	p.LockedUp = jp.LockedUp
+	p.Slandered = jp.Slandered
+	p.SlanderChips = jp.SlanderChips
+	p.Candidate = jp.Candidate
+	p.HasBid = jp.HasBid
+	p.UsedOffice = jp.UsedOffice
+	return p
+}
+
+// jGameLog provides the encoding of a game log.
+// Each entry is encoded together with the name under which its type is registered.
+type jGameLog GameLog
+
+type jEntry struct {
+	Type  string
+	Entry json.RawMessage
+}
+
+func (gl jGameLog) MarshalJSON() ([]byte, error) {
+	jes := make([]*jEntry, len(gl))
+	for i, e := range gl {
+		name, ok := entryNames[reflect.TypeOf(e)]
+		if !ok {
+			return nil, fmt.Errorf("unregistered log entry type %T", e)
+		}
+
+		bs, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		jes[i] = &jEntry{Type: name, Entry: bs}
+	}
+	return json.Marshal(jes)
+}
+
+func (gl *jGameLog) UnmarshalJSON(bs []byte) error {
+	var jes []*jEntry
+	err := json.Unmarshal(bs, &jes)
+	if err != nil {
+		return err
+	}
+
+	l := make(jGameLog, len(jes))
+	for i, je := range jes {
+		t, ok := entryTypes[je.Type]
+		if !ok {
+			return fmt.Errorf("unregistered log entry type %q", je.Type)
+		}
+
+		e := reflect.New(t.Elem()).Interface().(Entryer)
+		err = json.Unmarshal(je.Entry, e)
+		if err != nil {
+			return err
+		}
+		l[i] = e
+	}
+	*gl = l
+	return nil
+}
+
+var (
+	entryTypes = make(map[string]reflect.Type)
+	entryNames = make(map[reflect.Type]string)
+)
+
+// registerEntry registers the name used to identify the type of log entry e within encoded game state.
+// Like gob.RegisterName, the name of a registered type must never change once used to encode state.
+func registerEntry(name string, e Entryer) {
+	t := reflect.TypeOf(e)
+	entryTypes[name] = t
+	entryNames[t] = name
+}
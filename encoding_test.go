@@ -0,0 +1,141 @@
+package tammany
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/SlothNinja/codec"
+)
+
+// newElectedGame provides a game having played turns, resolved an election and scored victory points.
+func newElectedGame(t *testing.T) *Game {
+	g := newTestGame(t, 3)
+	playTurns(t, newTestClient(), g, 3)
+
+	p := g.Players()[0]
+	e := g.newResolvedElectionEntry(p)
+	e.WardID, e.Contested = g.ActiveWards()[0].ID, true
+	e.Bosses = BossesMap{p.ID(): 2}
+	e.PlayedChips = map[int]Chips{p.ID(): {irish: 1, german: 2}}
+
+	vp := g.newScoreVPEntry()
+	vp.ElectionResults = &electionResults{
+		PlayerResults: playerResults{p.ID(): {WardIDS: wardIDS{e.WardID}, Score: 1}},
+		MayorID:       p.ID(),
+	}
+	return g
+}
+
+// checkDecodedState fails the test unless s decodes to the state of game g, including its log.
+func checkDecodedState(t *testing.T, g *Game, s *State) {
+	want, err := encodeState(g.State)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := encodeState(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got state\n%s\nwant\n%s", got, want)
+	}
+
+	if len(s.Log) != len(g.Log) {
+		t.Fatalf("got %d log entries, want %d", len(s.Log), len(g.Log))
+	}
+	for i, e := range g.Log {
+		if reflect.TypeOf(s.Log[i]) != reflect.TypeOf(e) {
+			t.Errorf("got entry %d of type %T, want %T", i, s.Log[i], e)
+		}
+	}
+
+	n := len(g.Log)
+	e, ok := s.Log[n-2].(*resolvedElectionEntry)
+	want2 := g.Log[n-2].(*resolvedElectionEntry)
+	if !ok || e.WardID != want2.WardID || !e.Contested || !reflect.DeepEqual(e.Bosses, want2.Bosses) ||
+		!reflect.DeepEqual(e.PlayedChips, want2.PlayedChips) {
+		t.Errorf("got election entry %+v, want %+v", s.Log[n-2], want2)
+	}
+
+	vp, ok := s.Log[n-1].(*scoreVPEntry)
+	if !ok || !reflect.DeepEqual(vp.ElectionResults, g.Log[n-1].(*scoreVPEntry).ElectionResults) {
+		t.Errorf("got victory points entry %+v, want the election results of the game", s.Log[n-1])
+	}
+}
+
+func TestEncodeStateRoundTrip(t *testing.T) {
+	g := newElectedGame(t)
+
+	bs, err := encodeState(g.State)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, version, err := decodeState(bs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != stateSchemaVersion {
+		t.Errorf("got schema version %d, want %d", version, stateSchemaVersion)
+	}
+	checkDecodedState(t, g, s)
+}
+
+func TestDecodeGobState(t *testing.T) {
+	g := newElectedGame(t)
+
+	bs, err := codec.Encode(g.State)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, version, err := decodeState(bs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 0 {
+		t.Errorf("got schema version %d, want the legacy gob encoding", version)
+	}
+	checkDecodedState(t, g, s)
+}
+
+func TestMigrateGobState(t *testing.T) {
+	client, c := newTestClient(), testContext()
+	g := newElectedGame(t)
+
+	err := client.Store.Create(c, g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.SavedState, err = codec.Encode(g.State)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrated, err := client.migrateState(c, g)
+	if err != nil || !migrated {
+		t.Fatalf("got migrated %v and error %v, want migrated", migrated, err)
+	}
+
+	g2 := New(c, g.ID())
+	err = client.Store.Get(c, g2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, version, err := decodeState(g2.SavedState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != stateSchemaVersion {
+		t.Errorf("got schema version %d of migrated state, want %d", version, stateSchemaVersion)
+	}
+	checkDecodedState(t, g, s)
+
+	migrated, err = client.migrateState(c, g2)
+	if err != nil || migrated {
+		t.Errorf("got migrated %v and error %v on rerun, want the state untouched", migrated, err)
+	}
+}
@@ -2,22 +2,22 @@ package tammany
 
 import (
 	"encoding/gob"
-	"fmt"
 	"html/template"
 
 	"github.com/SlothNinja/contest"
 	"github.com/SlothNinja/game"
 	"github.com/SlothNinja/restful"
-	"github.com/SlothNinja/send"
 	"github.com/SlothNinja/user"
 	"github.com/gin-gonic/gin"
-	"github.com/mailjet/mailjet-apiv3-go"
 )
 
 func init() {
 	gob.RegisterName("*game.awardFavorChipPointsEntry", new(awardFavorChipPointsEntry))
+	registerEntry("awardFavorChipPoints", new(awardFavorChipPointsEntry))
 	gob.RegisterName("*game.awardSlanderChipPointsEntry", new(awardSlanderChipPointsEntry))
+	registerEntry("awardSlanderChipPoints", new(awardSlanderChipPointsEntry))
 	gob.RegisterName("*game.announceTHWinnersEntry", new(announceTHWinnersEntry))
+	registerEntry("announceTHWinners", new(announceTHWinnersEntry))
 }
 
 func (client *Client) startEndGamePhase(c *gin.Context, g *Game) ([]*contest.Contest, error) {
@@ -82,7 +82,18 @@ func (g *Game) newAwardFavorChipPointsEntryFor(p *Player) (e *awardFavorChipPoin
 }
 
 func (e *awardFavorChipPointsEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	return restful.HTML("%s scored 2 points for %s favor chips.", g.NameByPID(e.PlayerID), e.Chip)
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *awardFavorChipPointsEntry) Text(g *Game) string {
+	return g.T("%s scored 2 points for %s favor chips.", g.NameByPID(e.PlayerID), e.Chip)
+}
+
+func (e *awardFavorChipPointsEntry) Event() *Event {
+	ev := e.event(e)
+	ev.Chip = e.Chip.eventString()
+	ev.Points = 2
+	return ev
 }
 
 func (g *Game) awardSlanderChipPoints() {
@@ -113,7 +124,17 @@ func (g *Game) newAwardSlanderChipPointsEntryFor(p *Player) (e *awardSlanderChip
 }
 
 func (e *awardSlanderChipPointsEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	return restful.HTML("%s scored %v points for unused slander chips.", g.NameByPID(e.PlayerID), e.Scored)
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *awardSlanderChipPointsEntry) Text(g *Game) string {
+	return g.T("%s scored %v points for unused slander chips.", g.NameByPID(e.PlayerID), e.Scored)
+}
+
+func (e *awardSlanderChipPointsEntry) Event() *Event {
+	ev := e.event(e)
+	ev.Points = e.Scored
+	return ev
 }
 
 func (g *Game) setWinners(rmap contest.ResultsMap) {
@@ -141,11 +162,19 @@ func (g *Game) newAnnounceWinnersEntry() (e *announceTHWinnersEntry) {
 }
 
 func (e *announceTHWinnersEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *announceTHWinnersEntry) Text(g *Game) string {
 	names := make([]string, len(g.Winnerers()))
 	for i, winner := range g.Winnerers() {
 		names[i] = g.NameFor(winner)
 	}
-	return restful.HTML("Congratulations: %s.", restful.ToSentence(names))
+	return g.T("Congratulations: %s.", g.toSentence(names))
+}
+
+func (e *announceTHWinnersEntry) Event() *Event {
+	return e.event(e)
 }
 
 func (g *Game) winners() (ps Players) {
@@ -161,48 +190,115 @@ func (g *Game) winners() (ps Players) {
 	return
 }
 
-func (g *Game) sendEndGameNotifications(c *gin.Context) error {
-	ms := make([]mailjet.InfoMessagesV31, len(g.Players()))
-	subject := fmt.Sprintf("SlothNinja Games: Tammany Hall #%d Has Ended", g.ID)
+// sendEndGameNotifications notifies the players, and followers wanting notice of the end of the game, that the game
+// ended, including the projected rating changes rcs, which may be nil.
+// Failing to notify followers does not fail the notifications.
+func (client *Client) sendEndGameNotifications(c *gin.Context, g *Game, rcs map[int]*ratingChange) error {
+	build := func(lang string) (*Notification, error) {
+		return g.endGameNotification(c, lang, rcs)
+	}
 
-	var body string
-	body += `!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01 Transitional//EN">
-		<html>
-			<head>
-				<meta http-equiv="content-type" content="text/html; charset=ISO-8859-1">
-			</head>
-			<body bgcolor="#ffffff" text="#000000">`
+	var result error
 	for _, p := range g.Players() {
-		body += fmt.Sprintf("<p>%s scored %d points.</p>", g.NameFor(p), p.Score)
+		err := client.notify(c, g, p, build)
+		if err != nil {
+			client.Log.Warningf("unable to notify %s of end of game %d: %v", g.NameFor(p), g.ID(), err)
+			result = err
+		}
 	}
 
-	var names []string
+	client.notifyFollowers(c, g, func(f *Follow) bool { return f.GameEnd }, build)
+	return result
+}
+
+// standing provides the final place of a player.
+type standing struct {
+	Place  int
+	Name   string
+	Score  int
+	Winner bool
+}
+
+// standings provides the final places of the players, tied players sharing a place.
+func (g *Game) standings() []*standing {
+	winners := make(map[int]bool)
 	for _, p := range g.winners() {
-		names = append(names, g.NameFor(p))
+		winners[p.ID()] = true
 	}
-	body += fmt.Sprintf("<p>Congratulations to: %s.</p>", restful.ToSentence(names))
 
-	body += `
-			</body>
-		</html>`
+	ps := g.Players()
+	ss := make([]*standing, len(ps))
+	for i, p := range ps {
+		ss[i] = &standing{Place: i + 1, Name: g.NameFor(p), Score: p.Score, Winner: winners[p.ID()]}
+		if i > 0 && p.compare(ps[i-1]) == game.EqualTo {
+			ss[i].Place = ss[i-1].Place
+		}
+	}
+	return ss
+}
 
-	for i, p := range g.Players() {
+// ratingChange provides the projected rating of a player before and after the contests of a game.
+type ratingChange struct {
+	Name          string
+	Before, After float64
+}
+
+// Delta provides the change in rating.
+func (rc *ratingChange) Delta() float64 {
+	return rc.After - rc.Before
+}
+
+// ratingChanges projects the ratings of the players before and after the contests cs of the game, by player id.
+// The contests must not yet be saved, lest they be included in the rating before the game.
+func (client *Client) ratingChanges(c *gin.Context, g *Game, cs []*contest.Contest) (map[int]*ratingChange, error) {
+	rcs := make(map[int]*ratingChange, len(g.Players()))
+	for _, p := range g.Players() {
 		u := p.User()
-		ms[i] = mailjet.InfoMessagesV31{
-			From: &mailjet.RecipientV31{
-				Email: "webmaster@slothninja.com",
-				Name:  "Webmaster",
-			},
-			To: &mailjet.RecipientsV31{
-				mailjet.RecipientV31{
-					Email: u.Email,
-					Name:  u.Name,
-				},
-			},
-			Subject:  subject,
-			HTMLPart: body,
+		var ucs []*contest.Contest
+		for _, ct := range cs {
+			if ct.Key != nil && ct.Key.Parent.Equal(u.Key) {
+				ucs = append(ucs, ct)
+			}
+		}
+
+		before, err := client.Rating.GetProjected(c, u.Key, g.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		after, err := client.Rating.GetProjectedWith(c, u.Key, g.Type, ucs)
+		if err != nil {
+			return nil, err
 		}
+		rcs[p.ID()] = &ratingChange{Name: g.NameFor(p), Before: before.Low, After: after.Low}
 	}
-	_, err := send.Messages(c, ms...)
-	return err
+	return rcs, nil
+}
+
+func (g *Game) endGameNotification(c *gin.Context, lang string, rcs map[int]*ratingChange) (*Notification, error) {
+	g = g.withLanguage(lang)
+	note := g.newNotification(c, endGameNotice)
+
+	var winners []string
+	for _, p := range g.winners() {
+		winners = append(winners, g.NameFor(p))
+	}
+
+	var changes []*ratingChange
+	for _, p := range g.Players() {
+		if rc, ok := rcs[p.ID()]; ok {
+			changes = append(changes, rc)
+		}
+	}
+
+	err := renderNotification(note, lang, endGameNotice, struct {
+		GameID        int64
+		Title         string
+		Link          string
+		Standings     []*standing
+		Breakdowns    []*ScoreBreakdown
+		RatingChanges []*ratingChange
+		Winners       string
+	}{g.ID(), g.Title, note.Link, g.standings(), g.ScoreBreakdowns(), changes, g.toSentence(winners)})
+	return note, err
 }
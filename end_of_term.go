@@ -14,6 +14,7 @@ import (
 
 func init() {
 	gob.RegisterName("*game.scoreVPEntry", new(scoreVPEntry))
+	registerEntry("scoreVP", new(scoreVPEntry))
 }
 
 type electionResults struct {
@@ -59,7 +60,7 @@ func (g *Game) scoreVictoryPoints(c *gin.Context) {
 		for _, player := range g.Players() {
 			player.Office = noOffice
 		}
-		m.Score += 3
+		m.Score += mayorVP
 		m.Office = mayor
 		results.MayorID = m.ID()
 		g.setCurrentPlayers(m)
@@ -88,6 +89,10 @@ func (g *Game) newScoreVPEntry() *scoreVPEntry {
 //}
 
 func (e *scoreVPEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
+	if g.language() != defaultLanguage {
+		return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+	}
+
 	ts := restful.TemplatesFrom(c)
 	buf := new(bytes.Buffer)
 	tmpl := ts["tammany/score_vp_entry"]
@@ -101,6 +106,50 @@ func (e *scoreVPEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTM
 	return restful.HTML(buf.String())
 }
 
+func (e *scoreVPEntry) Text(g *Game) string {
+	if e.ElectionResults == nil {
+		return g.T("Victory points were scored.")
+	}
+
+	var ss []string
+	for _, p := range g.Players() {
+		result, ok := e.ElectionResults.PlayerResults[p.ID()]
+		if !ok {
+			continue
+		}
+		if count := len(result.WardIDS); count == 1 {
+			ss = append(ss, g.T("%s won 1 ward and scored %d points", g.NameFor(p), result.Score))
+		} else {
+			ss = append(ss, g.T("%s won %d wards and scored %d points", g.NameFor(p), count, result.Score))
+		}
+	}
+
+	s := g.toSentence(ss) + "."
+	if e.ElectionResults.MayorID != noPlayerID {
+		s += " " + g.T("%s was elected mayor.", g.NameByPID(e.ElectionResults.MayorID))
+	}
+	return s
+}
+
+func (e *scoreVPEntry) Event() *Event {
+	ev := e.event(e)
+	if e.ElectionResults == nil {
+		return ev
+	}
+
+	for pid, result := range e.ElectionResults.PlayerResults {
+		pe := ev.player(pid)
+		pe.Score = result.Score
+		for _, wid := range result.WardIDS {
+			pe.Wards = append(pe.Wards, int(wid))
+		}
+	}
+	if mid := e.ElectionResults.MayorID; mid != noPlayerID {
+		ev.player(mid).Mayor = true
+	}
+	return ev
+}
+
 //	s :=
 //		`       <div>
 //                <ul>
@@ -0,0 +1,265 @@
+package tammany
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Event provides a typed, JSON-serializable view of an entry of the game log.
+// Fields not pertinent to the kind of entry are left at their zero value.
+type Event struct {
+	Kind          string               `json:"kind"`
+	Year          int                  `json:"year"`
+	Turn          int                  `json:"turn"`
+	Phase         string               `json:"phase"`
+	PlayerID      int                  `json:"playerId"`
+	OtherPlayerID int                  `json:"otherPlayerId"`
+	WardID        int                  `json:"wardId,omitempty"`
+	ToWardID      int                  `json:"toWardId,omitempty"`
+	Nationality   string               `json:"nationality,omitempty"`
+	Chip          string               `json:"chip,omitempty"`
+	Office        string               `json:"office,omitempty"`
+	Bosses        int                  `json:"bosses,omitempty"`
+	Chips         int                  `json:"chips,omitempty"`
+	Points        int                  `json:"points,omitempty"`
+	Contested     bool                 `json:"contested,omitempty"`
+	Immigrants    map[string]int       `json:"immigrants,omitempty"`
+	Players       map[int]*PlayerEvent `json:"players,omitempty"`
+	Reason        string               `json:"reason,omitempty"`
+	Changes       []*AdminChange       `json:"changes,omitempty"`
+	CreatedAt     time.Time            `json:"createdAt"`
+}
+
+// PlayerEvent provides the part of an event pertaining to a single player,
+// e.g. the bosses and chips of a player in a ward election.
+type PlayerEvent struct {
+	Bosses int            `json:"bosses,omitempty"`
+	Chips  map[string]int `json:"chips,omitempty"`
+	Wards  []int          `json:"wards,omitempty"`
+	Score  int            `json:"score,omitempty"`
+	Mayor  bool           `json:"mayor,omitempty"`
+}
+
+// event provides an event for entry outer, which embeds e, populated with the fields common to all entries.
+func (e *Entry) event(outer Entryer) *Event {
+	return &Event{
+		Kind:          entryNames[reflect.TypeOf(outer)],
+		Year:          e.Round(),
+		Turn:          e.Turn(),
+		Phase:         phaseNames[e.Phase()],
+		PlayerID:      e.PlayerID,
+		OtherPlayerID: e.OtherPlayerID,
+		CreatedAt:     e.CreatedAt(),
+	}
+}
+
+func (ev *Event) player(pid int) *PlayerEvent {
+	if ev.Players == nil {
+		ev.Players = make(map[int]*PlayerEvent)
+	}
+	pe, ok := ev.Players[pid]
+	if !ok {
+		pe = new(PlayerEvent)
+		ev.Players[pid] = pe
+	}
+	return pe
+}
+
+func (n nationality) eventString() string {
+	if n == noNationality {
+		return ""
+	}
+	return n.String()
+}
+
+func (o office) eventString() string {
+	if o == noOffice {
+		return ""
+	}
+	return o.String()
+}
+
+func nationalCounts(counts map[nationality]int) map[string]int {
+	var m map[string]int
+	for n, count := range counts {
+		if count == 0 || n == noNationality {
+			continue
+		}
+		if m == nil {
+			m = make(map[string]int)
+		}
+		m[n.String()] = count
+	}
+	return m
+}
+
+// Events provides the events of the entries of the log.
+func (gl GameLog) Events() []*Event {
+	evs := make([]*Event, len(gl))
+	for i, e := range gl {
+		evs[i] = e.Event()
+	}
+	return evs
+}
+
+// EventFilter reports whether an event satisfies the filter.
+type EventFilter func(*Event) bool
+
+// Filter returns the entries of the log whose events satisfy every filter.
+func (gl GameLog) Filter(fs ...EventFilter) GameLog {
+	var l GameLog
+	for _, e := range gl {
+		if matchesAll(e.Event(), fs) {
+			l = append(l, e)
+		}
+	}
+	return l
+}
+
+func matchesAll(ev *Event, fs []EventFilter) bool {
+	for _, f := range fs {
+		if !f(ev) {
+			return false
+		}
+	}
+	return true
+}
+
+// ByKind filters events having one of the kinds.
+func ByKind(kinds ...string) EventFilter {
+	return func(ev *Event) bool {
+		for _, kind := range kinds {
+			if ev.Kind == kind {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ByPlayer filters events performed by, targeting, or otherwise involving the player.
+func ByPlayer(pid int) EventFilter {
+	return func(ev *Event) bool {
+		if ev.PlayerID == pid || ev.OtherPlayerID == pid {
+			return true
+		}
+		_, ok := ev.Players[pid]
+		return ok
+	}
+}
+
+// ByWard filters events occurring in the ward.
+func ByWard(wid int) EventFilter {
+	return func(ev *Event) bool {
+		return ev.WardID == wid || ev.ToWardID == wid
+	}
+}
+
+// ByNationality filters events involving immigrants or favor chips of the nationality,
+// which may be given as the name of the nationality (e.g. "Irish") or its abbreviation (e.g. "Ger").
+func ByNationality(name string) EventFilter {
+	n, err := parseNationality(name)
+	if err != nil {
+		return func(*Event) bool { return false }
+	}
+
+	s := n.String()
+	return func(ev *Event) bool {
+		if ev.Nationality == s || ev.Chip == s || ev.Immigrants[s] > 0 {
+			return true
+		}
+		for _, pe := range ev.Players {
+			if pe.Chips[s] > 0 {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ByYear filters events occurring during the year.
+func ByYear(year int) EventFilter {
+	return func(ev *Event) bool {
+		return ev.Year == year
+	}
+}
+
+// eventFilters returns the filters provided by query parameters kind, player, ward, nationality, and year.
+func eventFilters(c *gin.Context) ([]EventFilter, error) {
+	var fs []EventFilter
+	if v := c.Query("kind"); v != "" {
+		fs = append(fs, ByKind(strings.Split(v, ",")...))
+	}
+
+	ints := []struct {
+		param  string
+		filter func(int) EventFilter
+	}{
+		{"player", ByPlayer},
+		{"ward", ByWard},
+		{"year", ByYear},
+	}
+	for _, i := range ints {
+		v := c.Query(i.param)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, i.filter(n))
+	}
+
+	if v := c.Query("nationality"); v != "" {
+		fs = append(fs, ByNationality(v))
+	}
+	return fs, nil
+}
+
+// events provides the events of the game log as JSON, filtered by the query parameters of the request.
+func (client *Client) events(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		id, err := getID(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		fs, err := eventFilters(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		g := New(c, id)
+		err = client.dsGet(c, g)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		cu, err := client.User.Current(c)
+		if err != nil {
+			client.Log.Debugf(err.Error())
+		}
+
+		g, err = client.spectatorView(c, g, cu)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, g.Log.Filter(fs...).Events())
+	}
+}
@@ -0,0 +1,320 @@
+package tammany
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/datastore"
+	"github.com/SlothNinja/codec"
+	"github.com/SlothNinja/game"
+	"github.com/SlothNinja/mlog"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	gamesDir     = "games"
+	entitiesDir  = "entities"
+	snapshotsDir = "snapshots"
+	gameExt      = ".gob"
+)
+
+// fileStore provides a GameStore that keeps each game in a file of a local directory.
+// Intended for local development, fileStore assumes a single process accesses the directory.
+type fileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStore returns a GameStore that keeps games in files within directory dir.
+func NewFileStore(dir string) (GameStore, error) {
+	for _, sub := range []string{gamesDir, entitiesDir, snapshotsDir} {
+		err := os.MkdirAll(filepath.Join(dir, sub), 0755)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+func (s *fileStore) gamePath(id int64) string {
+	return filepath.Join(s.dir, gamesDir, strconv.FormatInt(id, 10)+gameExt)
+}
+
+func (s *fileStore) entityPath(k *datastore.Key) string {
+	return filepath.Join(s.dir, entitiesDir, k.Encode()+gameExt)
+}
+
+// snapshotsPath provides the directory holding the snapshots of the game having id id.
+func (s *fileStore) snapshotsPath(id int64) string {
+	return filepath.Join(s.dir, snapshotsDir, strconv.FormatInt(id, 10))
+}
+
+func (s *fileStore) Get(c *gin.Context, g *Game) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.get(g)
+}
+
+// get presumes caller holds lock.
+func (s *fileStore) get(g *Game) error {
+	bs, err := ioutil.ReadFile(s.gamePath(g.ID()))
+	if os.IsNotExist(err) {
+		return ErrGameNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return codec.Decode(g.Header, bs)
+}
+
+func (s *fileStore) Put(c *gin.Context, g *Game, ks []*datastore.Key, es []interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	oldG := New(c, g.ID())
+	err := s.get(oldG)
+	if err != nil {
+		return err
+	}
+
+	if !oldG.UpdatedAt.Equal(g.UpdatedAt) {
+		return ErrGameChanged
+	}
+
+	for i, e := range es {
+		bs, err := codec.Encode(e)
+		if err != nil {
+			return err
+		}
+		err = writeFile(s.entityPath(ks[i]), bs)
+		if err != nil {
+			return err
+		}
+	}
+	return s.put(g)
+}
+
+func (s *fileStore) Create(c *gin.Context, g *Game, ms []*mlog.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.ids()
+	if err != nil {
+		return err
+	}
+
+	var lastID int64
+	for _, id := range ids {
+		if id > lastID {
+			lastID = id
+		}
+	}
+
+	g.Key = newKey(c, lastID+1)
+
+	ml := mlog.New(g.ID())
+	ml.Messages = ms
+	err = s.putMLog(ml)
+	if err != nil {
+		return err
+	}
+	return s.put(g)
+}
+
+func (s *fileStore) GetMLog(c *gin.Context, id int64) (*mlog.MLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ml := mlog.New(id)
+	bs, err := ioutil.ReadFile(s.entityPath(ml.Key))
+	if os.IsNotExist(err) {
+		return nil, mlog.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = codec.Decode(ml, bs)
+	return ml, err
+}
+
+func (s *fileStore) PutMLog(c *gin.Context, ml *mlog.MLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.putMLog(ml)
+}
+
+// putMLog presumes caller holds lock.
+func (s *fileStore) putMLog(ml *mlog.MLog) error {
+	bs, err := codec.Encode(ml)
+	if err != nil {
+		return err
+	}
+	return writeFile(s.entityPath(ml.Key), bs)
+}
+
+// put presumes caller holds lock.
+func (s *fileStore) put(g *Game) error {
+	touch(g.Header)
+	bs, err := codec.Encode(g.Header)
+	if err != nil {
+		return err
+	}
+	return writeFile(s.gamePath(g.ID()), bs)
+}
+
+// ids presumes caller holds lock.
+func (s *fileStore) ids() ([]int64, error) {
+	fis, err := ioutil.ReadDir(filepath.Join(s.dir, gamesDir))
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, fi := range fis {
+		name := fi.Name()
+		if !strings.HasSuffix(name, gameExt) {
+			continue
+		}
+		id, err := strconv.ParseInt(strings.TrimSuffix(name, gameExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (s *fileStore) ListByStatus(c *gin.Context, status game.Status) (Games, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.ids()
+	if err != nil {
+		return nil, err
+	}
+
+	var gs Games
+	for _, id := range ids {
+		g := New(c, id)
+		err := s.get(g)
+		if err != nil {
+			return nil, err
+		}
+		if g.Status == status {
+			gs = append(gs, g)
+		}
+	}
+	sort.Sort(byUpdatedAt{gs})
+	return gs, nil
+}
+
+func (s *fileStore) ListByUser(c *gin.Context, status game.Status, uid int64) (Games, error) {
+	gs, err := s.ListByStatus(c, status)
+	if err != nil {
+		return nil, err
+	}
+
+	var ugs Games
+	for _, g := range gs {
+		if hasUser(g, uid) {
+			ugs = append(ugs, g)
+		}
+	}
+	return ugs, nil
+}
+
+func (s *fileStore) PutSnapshot(c *gin.Context, g *Game, snap *Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := s.snapshotsPath(g.ID())
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	bs, err := codec.Encode(snap)
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(dir, strconv.Itoa(snap.LogLength)+gameExt), bs)
+}
+
+func (s *fileStore) Snapshots(c *gin.Context, g *Game) ([]*Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.snapshots(g)
+}
+
+// snapshots presumes caller holds lock.
+func (s *fileStore) snapshots(g *Game) ([]*Snapshot, error) {
+	dir := s.snapshotsPath(g.ID())
+	fis, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snaps []*Snapshot
+	for _, fi := range fis {
+		if !strings.HasSuffix(fi.Name(), gameExt) {
+			continue
+		}
+
+		bs, err := ioutil.ReadFile(filepath.Join(dir, fi.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		snap := new(Snapshot)
+		err = codec.Decode(snap, bs)
+		if err != nil {
+			return nil, err
+		}
+		snap.Key = snapshotKey(g.Key, snap.LogLength)
+		snaps = append(snaps, snap)
+	}
+	sort.Sort(bySnapshotLogLength(snaps))
+	return snaps, nil
+}
+
+func (s *fileStore) DeleteSnapshots(c *gin.Context, g *Game, logLength int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snaps, err := s.snapshots(g)
+	if err != nil {
+		return err
+	}
+
+	for _, snap := range snaps {
+		if snap.LogLength > logLength {
+			err = os.Remove(filepath.Join(s.snapshotsPath(g.ID()), strconv.Itoa(snap.LogLength)+gameExt))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeFile writes bs to a temporary file that then replaces the file at path,
+// so that readers never observe a partially written file.
+func writeFile(path string, bs []byte) error {
+	tmp := path + ".tmp"
+	err := ioutil.WriteFile(tmp, bs, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/SlothNinja/game"
 	"github.com/SlothNinja/log"
 	"github.com/SlothNinja/restful"
-	"github.com/SlothNinja/sn"
 	"github.com/SlothNinja/user"
 	"github.com/gin-gonic/gin"
 )
@@ -26,74 +25,101 @@ func (client *Client) finish(prefix string) gin.HandlerFunc {
 			return
 		}
 
-		oldCP := g.CurrentPlayerFor(cu)
-
-		var (
-			s  *user.Stats
-			cs []*contest.Contest
-		)
-
-		switch g.Phase {
-		case actions:
-			s, cs, err = client.actionsPhaseFinishTurn(c, g, cu)
-		case placeImmigrant:
-			s, cs, err = client.placeImmigrantPhaseFinishTurn(c, g, cu)
-		case takeFavorChip:
-			s, cs, err = client.takeChipPhaseFinishTurn(c, g, cu)
-		case elections:
-			s, cs, err = client.electionPhaseFinishTurn(c, g, cu)
-		case assignCityOffices:
-			s, err = g.assignOfficesPhaseFinishTurn(c, cu)
-		default:
-			err = sn.NewVError("Improper Phase for finishing turn.")
-		}
-
+		err = client.finishAndSave(c, g, cu)
 		if err != nil {
 			client.Log.Errorf(err.Error())
-			c.Redirect(http.StatusSeeOther, showPath(prefix, c.Param(hParam)))
-			return
 		}
+		c.Redirect(http.StatusSeeOther, showPath(prefix, c.Param(hParam)))
+	}
+}
 
-		// cs != nil then game over
-		if cs != nil {
-			g.Phase = gameOver
-			g.Status = game.Completed
-			s = s.GetUpdate(c, g.UpdatedAt)
-			ks, es := wrap(s, cs)
-			err = client.saveWith(c, g, cu, ks, es)
-			if err != nil {
-				client.Log.Errorf(err.Error())
-				c.Redirect(http.StatusSeeOther, showPath(prefix, c.Param(hParam)))
-				return
-			}
-			err = g.sendEndGameNotifications(c)
-			if err != nil {
-				client.Log.Warningf(err.Error())
-			}
-			c.Redirect(http.StatusSeeOther, showPath(prefix, c.Param(hParam)))
-			return
-		}
+// finishAndSave finishes the turn of the current user and saves the game,
+// notifying the next player or, once the game ends, all players.
+func (client *Client) finishAndSave(c *gin.Context, g *Game, cu *user.User) error {
+	oldCP, year, logLen := g.CurrentPlayerFor(cu), g.Year(), len(g.Log)
 
+	s, cs, err := client.finishTurn(c, g, cu)
+	if err != nil {
+		return err
+	}
+
+	if !g.InOfficeWarningSubPhase() {
+		g.recordFinish(oldCP, year)
+	}
+
+	// cs != nil then game over
+	if cs != nil {
+		g.endGame()
+		r, err := client.Audit(c, g)
+		switch {
+		case err != nil:
+			client.Log.Warningf("unable to audit game %d: %v", g.ID(), err)
+		case !r.Consistent:
+			client.Log.Warningf("game %d completed with %d divergences from its replay", g.ID(), len(r.Divergences))
+		}
+		rcs, err := client.ratingChanges(c, g, cs)
+		if err != nil {
+			client.Log.Warningf("unable to project rating changes for game %d: %v", g.ID(), err)
+		}
 		s = s.GetUpdate(c, g.UpdatedAt)
-		err = client.saveWith(c, g, cu, []*datastore.Key{s.Key}, []interface{}{s})
+		ks, es := wrap(s, cs)
+		err = client.saveWith(c, g, cu, ks, es)
 		if err != nil {
-			client.Log.Errorf(err.Error())
-			c.Redirect(http.StatusSeeOther, showPath(prefix, c.Param(hParam)))
-			return
+			return err
+		}
+		for _, uid := range g.UserIDS {
+			client.Cache.Delete(statsKey(uid))
 		}
+		client.sendElectionNotifications(c, g, logLen)
+		err = client.sendEndGameNotifications(c, g, rcs)
+		if err != nil {
+			client.Log.Warningf(err.Error())
+		}
+		return nil
+	}
 
-		newCP := g.CurrentPlayer()
-		if newCP != nil && oldCP.ID() != newCP.ID() {
-			err = g.SendTurnNotificationsTo(c, newCP)
-			if err != nil {
-				client.Log.Warningf(err.Error())
-			}
+	s = s.GetUpdate(c, g.UpdatedAt)
+	err = client.saveWith(c, g, cu, []*datastore.Key{s.Key}, []interface{}{s})
+	if err != nil {
+		return err
+	}
+
+	client.sendElectionNotifications(c, g, logLen)
+
+	newCP := g.CurrentPlayer()
+	if newCP != nil && oldCP.ID() != newCP.ID() {
+		err = client.sendTurnNotifications(c, g, newCP)
+		if err != nil {
+			client.Log.Warningf(err.Error())
 		}
+	}
+	return nil
+}
 
-		c.Redirect(http.StatusSeeOther, showPath(prefix, c.Param(hParam)))
+// finishTurn finishes the turn of the current user, providing contests once the game ends.
+func (client *Client) finishTurn(c *gin.Context, g *Game, cu *user.User) (*user.Stats, []*contest.Contest, error) {
+	switch g.Phase {
+	case actions:
+		return client.actionsPhaseFinishTurn(c, g, cu)
+	case placeImmigrant:
+		return client.placeImmigrantPhaseFinishTurn(c, g, cu)
+	case takeFavorChip:
+		return client.takeChipPhaseFinishTurn(c, g, cu)
+	case elections:
+		return client.electionPhaseFinishTurn(c, g, cu)
+	case assignCityOffices:
+		s, err := g.assignOfficesPhaseFinishTurn(c, cu)
+		return s, nil, err
+	default:
+		return nil, nil, g.verror(ErrWrongPhase, "Improper Phase for finishing turn.")
 	}
 }
 
+func (g *Game) endGame() {
+	g.Phase = gameOver
+	g.Status = game.Completed
+}
+
 func (g *Game) validateFinishTurn(c *gin.Context, cu *user.User) (*user.Stats, error) {
 	log.Debugf(msgEnter)
 	defer log.Debugf(msgExit)
@@ -101,15 +127,15 @@ func (g *Game) validateFinishTurn(c *gin.Context, cu *user.User) (*user.Stats, e
 	cp, s := g.CurrentPlayerFor(cu), user.StatsFetched(c)
 	switch {
 	case s == nil:
-		return nil, sn.NewVError("missing stats for player.")
+		return nil, g.verror(ErrMissingUser, "missing stats for player.")
 	case cu == nil:
-		return nil, sn.NewVError("missing current user.")
+		return nil, g.verror(ErrMissingUser, "missing current user.")
 	case cp == nil || !cp.IsCurrentUser(cu):
-		return nil, sn.NewVError("Only the current player may finish a turn.")
+		return nil, g.verror(ErrNotCurrentPlayer, "Only the current player may finish a turn.")
 	case !cp.PerformedAction:
-		return nil, sn.NewVError("%s has yet to perform an action.", cu.Name)
+		return nil, g.verror(ErrActionIncomplete, "%s has yet to perform an action.", cu.Name)
 	case g.ImmigrantInTransit != noNationality:
-		return nil, sn.NewVError("You must complete move of %s immigrant before finishing turn.", g.ImmigrantInTransit)
+		return nil, g.verror(ErrActionIncomplete, "You must complete move of %s immigrant before finishing turn.", g.ImmigrantInTransit)
 	default:
 		return s, nil
 	}
@@ -158,6 +184,7 @@ func (client *Client) actionsPhaseFinishTurn(c *gin.Context, g *Game, cu *user.U
 				return s, cs, nil
 			}
 		default:
+			g.markPhaseBoundary()
 			g.setYear(g.Year() + 1)
 		}
 	}
@@ -175,7 +202,7 @@ func (g *Game) validateActionsPhaseFinishTurn(c *gin.Context, cu *user.User) (*u
 		return nil, err
 	}
 	if g.Phase != actions {
-		return nil, sn.NewVError(`Expected "Actions" phase but have %q phase.`, g.Phase)
+		return nil, g.verror(ErrWrongPhase, `Expected "Actions" phase but have %q phase.`, g.Phase)
 	}
 	return s, nil
 }
@@ -222,7 +249,7 @@ func (g *Game) validateElectionPhaseFinishTurn(c *gin.Context, cu *user.User) (*
 	case err != nil:
 		return nil, err
 	case g.Phase != elections:
-		return nil, sn.NewVError(`Expected "Elections" phase but have %q phase.`, g.Phase)
+		return nil, g.verror(ErrWrongPhase, `Expected "Elections" phase but have %q phase.`, g.Phase)
 	default:
 		return s, nil
 	}
@@ -269,7 +296,7 @@ func (g *Game) validatePlaceImmigrantPhaseFinishTurn(c *gin.Context, cu *user.Us
 	case err != nil:
 		return nil, err
 	case g.Phase != placeImmigrant:
-		return nil, sn.NewVError(`Expected "Place Immigrant" phase but have %q phase.`, g.Phase)
+		return nil, g.verror(ErrWrongPhase, `Expected "Place Immigrant" phase but have %q phase.`, g.Phase)
 	default:
 		return s, nil
 	}
@@ -295,7 +322,7 @@ func (g *Game) validateTakeChipPhaseFinishTurn(c *gin.Context, cu *user.User) (*
 	case err != nil:
 		return nil, err
 	case g.Phase != takeFavorChip:
-		return nil, sn.NewVError(`Expected "Take Favor Chip" phase but have %q phase.`, g.Phase)
+		return nil, g.verror(ErrWrongPhase, `Expected "Take Favor Chip" phase but have %q phase.`, g.Phase)
 	default:
 		return s, nil
 	}
@@ -320,9 +347,9 @@ func (g *Game) validateAssignOfficesPhaseFinishTurn(c *gin.Context, cu *user.Use
 	case err != nil:
 		return nil, err
 	case g.Phase != assignCityOffices:
-		return nil, sn.NewVError(`Expected "Assign City Offices" phase but have %q phase.`, g.Phase)
+		return nil, g.verror(ErrWrongPhase, `Expected "Assign City Offices" phase but have %q phase.`, g.Phase)
 	case !g.allPlayersHaveOffice():
-		return nil, sn.NewVError("You must first assign all players an office")
+		return nil, g.verror(ErrActionIncomplete, "You must first assign all players an office")
 	default:
 		return s, nil
 	}
@@ -0,0 +1,293 @@
+package tammany
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/datastore"
+	"github.com/gin-gonic/gin"
+)
+
+const followKind = "TammanyFollow"
+
+// Follow provides the following of a game by a user not playing the game.
+// A follower is notified of the results of elections if Elections is set,
+// and of the end of the game if GameEnd is set.
+type Follow struct {
+	Key       *datastore.Key `datastore:"__key__"`
+	GameID    int64
+	UserID    int64
+	Elections bool
+	GameEnd   bool
+	CreatedAt time.Time
+}
+
+func followKey(gid, uid int64) *datastore.Key {
+	return datastore.NameKey(followKind, fmt.Sprintf("%d-%d", gid, uid), nil)
+}
+
+func newFollow(gid, uid int64) *Follow {
+	return &Follow{Key: followKey(gid, uid), GameID: gid, UserID: uid}
+}
+
+func (f *Follow) view() gin.H {
+	return gin.H{"gameId": f.GameID, "elections": f.Elections, "gameEnd": f.GameEnd, "createdAt": f.CreatedAt}
+}
+
+// FollowStore provides persistence of the games followed by users.
+type FollowStore interface {
+	// PutFollow saves the follow, replacing any follow of the game by the user.
+	PutFollow(*gin.Context, *Follow) error
+
+	// DeleteFollow deletes the follow of the game by the user, if any.
+	DeleteFollow(*gin.Context, int64, int64) error
+
+	// FollowersOf returns the follows of the game.
+	FollowersOf(*gin.Context, int64) ([]*Follow, error)
+
+	// FollowedBy returns the follows of the user, most recent first.
+	FollowedBy(*gin.Context, int64) ([]*Follow, error)
+}
+
+// WithFollowStore sets the store used to persist follows.
+func (client *Client) WithFollowStore(s FollowStore) *Client {
+	client.Follows = s
+	return client
+}
+
+type dsFollowStore struct {
+	*datastore.Client
+}
+
+func newDSFollowStore(dsClient *datastore.Client) *dsFollowStore {
+	return &dsFollowStore{Client: dsClient}
+}
+
+func (s *dsFollowStore) PutFollow(c *gin.Context, f *Follow) error {
+	if f.CreatedAt.IsZero() {
+		f.CreatedAt = time.Now()
+	}
+	_, err := s.Put(c, f.Key, f)
+	return err
+}
+
+func (s *dsFollowStore) DeleteFollow(c *gin.Context, gid, uid int64) error {
+	return s.Delete(c, followKey(gid, uid))
+}
+
+func (s *dsFollowStore) FollowersOf(c *gin.Context, gid int64) ([]*Follow, error) {
+	q := datastore.NewQuery(followKind).Filter("GameID=", gid)
+
+	var fs []*Follow
+	_, err := s.GetAll(c, q, &fs)
+	return fs, err
+}
+
+func (s *dsFollowStore) FollowedBy(c *gin.Context, uid int64) ([]*Follow, error) {
+	q := datastore.NewQuery(followKind).Filter("UserID=", uid)
+
+	var fs []*Follow
+	_, err := s.GetAll(c, q, &fs)
+	sortFollows(fs)
+	return fs, err
+}
+
+// sortFollows sorts the follows, most recent first.
+// Follows are sorted in memory, so the datastore requires no composite index.
+func sortFollows(fs []*Follow) {
+	sort.Slice(fs, func(i, j int) bool { return fs[i].CreatedAt.After(fs[j].CreatedAt) })
+}
+
+// memoryFollowStore provides an in-memory FollowStore for local development and tests.
+type memoryFollowStore struct {
+	mu      sync.Mutex
+	follows map[string]Follow
+}
+
+// NewMemoryFollowStore returns a FollowStore that keeps follows in memory.
+func NewMemoryFollowStore() FollowStore {
+	return &memoryFollowStore{follows: make(map[string]Follow)}
+}
+
+func (s *memoryFollowStore) PutFollow(c *gin.Context, f *Follow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.CreatedAt.IsZero() {
+		f.CreatedAt = time.Now()
+	}
+	s.follows[f.Key.Name] = *f
+	return nil
+}
+
+func (s *memoryFollowStore) DeleteFollow(c *gin.Context, gid, uid int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.follows, followKey(gid, uid).Name)
+	return nil
+}
+
+func (s *memoryFollowStore) FollowersOf(c *gin.Context, gid int64) ([]*Follow, error) {
+	return s.filter(func(f *Follow) bool { return f.GameID == gid }), nil
+}
+
+func (s *memoryFollowStore) FollowedBy(c *gin.Context, uid int64) ([]*Follow, error) {
+	fs := s.filter(func(f *Follow) bool { return f.UserID == uid })
+	sortFollows(fs)
+	return fs, nil
+}
+
+func (s *memoryFollowStore) filter(keep func(*Follow) bool) []*Follow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var fs []*Follow
+	for _, f := range s.follows {
+		f2 := f
+		if keep(&f2) {
+			fs = append(fs, &f2)
+		}
+	}
+	return fs
+}
+
+// follow makes the current user a follower of the game, or updates the notifications of an existing follow.
+func (client *Client) follow(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil || cu == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You must be logged in to follow a game."})
+			return
+		}
+
+		g := gameFrom(c)
+		if g == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": ErrGameNotFound.Error()})
+			return
+		}
+
+		if g.HasUser(cu) {
+			c.JSON(http.StatusConflict, gin.H{"error": "You may not follow a game in which you play."})
+			return
+		}
+
+		obj := struct {
+			Elections bool `form:"elections" json:"elections"`
+			GameEnd   bool `form:"gameEnd" json:"gameEnd"`
+		}{}
+
+		err = c.ShouldBind(&obj)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		f := newFollow(g.ID(), cu.ID())
+		f.Elections, f.GameEnd = obj.Elections, obj.GameEnd
+		err = client.Follows.PutFollow(c, f)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, f.view())
+	}
+}
+
+// unfollow ends the following of the game by the current user.
+func (client *Client) unfollow(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil || cu == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You must be logged in to unfollow a game."})
+			return
+		}
+
+		id, err := getID(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = client.Follows.DeleteFollow(c, id, cu.ID())
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// followed provides the games followed by the current user.
+func (client *Client) followed(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil || cu == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You must be logged in to view the games you follow."})
+			return
+		}
+
+		fs, err := client.Follows.FollowedBy(c, cu.ID())
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		views := make([]gin.H, len(fs))
+		for i, f := range fs {
+			views[i] = f.view()
+		}
+		c.JSON(http.StatusOK, views)
+	}
+}
+
+// notifyFollowers delivers the notification built in the language preferred by each follower of the game
+// for whom want reports true.
+func (client *Client) notifyFollowers(c *gin.Context, g *Game, want func(*Follow) bool, build func(lang string) (*Notification, error)) {
+	fs, err := client.Follows.FollowersOf(c, g.ID())
+	if err != nil {
+		client.Log.Warningf("unable to get followers of game %d: %v", g.ID(), err)
+		return
+	}
+
+	var uids []int64
+	for _, f := range fs {
+		if want(f) {
+			uids = append(uids, f.UserID)
+		}
+	}
+	if len(uids) == 0 {
+		return
+	}
+
+	us, err := client.User.GetMulti(c, uids)
+	if err != nil {
+		client.Log.Warningf("unable to get followers of game %d: %v", g.ID(), err)
+		return
+	}
+
+	for _, u := range us {
+		if u == nil || g.HasUser(u) {
+			continue
+		}
+		err := client.notifyUser(c, u.ID(), u.Email, u.Name, build)
+		if err != nil {
+			client.Log.Warningf("unable to notify follower %s of game %d: %v", u.Name, g.ID(), err)
+		}
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/SlothNinja/game"
 	"github.com/SlothNinja/log"
 	"github.com/SlothNinja/restful"
-	"github.com/SlothNinja/sn"
 	gtype "github.com/SlothNinja/type"
 	"github.com/SlothNinja/user"
 	"github.com/gin-gonic/gin"
@@ -29,6 +28,9 @@ const noPlayerID = game.NoPlayerID
 type Game struct {
 	*game.Header
 	*State
+
+	// lang provides the language of the user viewing the game.
+	lang string
 }
 
 // State stores the game state of a Tammany Hall game.
@@ -48,6 +50,23 @@ type State struct {
 	SlanderNationality nationality
 
 	ConfirmedOffice bool
+
+	// Actions records, in move notation, the actions performed since the game started.
+	Actions []string
+
+	// Draws records, in order, the immigrants drawn at random since the game started.
+	Draws []nationality
+
+	// SpectatorDelay provides the number of actions by which the view of users not playing the game lags the game.
+	SpectatorDelay int
+
+	phaseBoundary bool
+
+	// snapshots holds the snapshots taken since the game was saved.
+	snapshots []*Snapshot
+
+	// redraws holds the immigrants that a replay of the game draws in place of random draws.
+	redraws []nationality
 }
 
 const noWardID wardID = -1
@@ -181,6 +200,7 @@ func (g *Game) start(c *gin.Context) {
 	g.CastleGarden = defaultNationals()
 	g.immigration()
 	g.castleGardenPhase()
+	g.takeSnapshot()
 }
 
 func (g *Game) addNewPlayer(id int) {
@@ -189,6 +209,7 @@ func (g *Game) addNewPlayer(id int) {
 }
 
 func (g *Game) startNextTerm() {
+	g.markPhaseBoundary()
 	g.setYear(g.Year() + 1)
 
 	for _, p := range g.Players() {
@@ -215,6 +236,7 @@ func (client *Client) startElections(c *gin.Context, g *Game, cu *user.User) ([]
 	client.Log.Debugf(msgEnter)
 	defer client.Log.Debugf(msgExit)
 
+	g.markPhaseBoundary()
 	g.Phase = elections
 	g.SubPhase = noSubPhase
 	g.emptyGarden()
@@ -296,7 +318,7 @@ func (g *Game) playerByIndex(i int) (p *Player) {
 func (g *Game) undoAction(c *gin.Context, cu *user.User) (string, game.ActionType, error) {
 	cp := g.CurrentPlayer()
 	if !g.IsCurrentPlayer(cu) {
-		return "", game.None, sn.NewVError("Only the current player may perform this action.")
+		return "", game.None, g.verror(ErrNotCurrentPlayer, "Only the current player may perform this action.")
 	}
 
 	restful.AddNoticef(c, "%s undid action.", g.NameFor(cp))
@@ -306,7 +328,7 @@ func (g *Game) undoAction(c *gin.Context, cu *user.User) (string, game.ActionTyp
 func (g *Game) resetTurn(c *gin.Context, cu *user.User) (string, game.ActionType, error) {
 	cp := g.CurrentPlayer()
 	if !g.IsCurrentPlayer(cu) {
-		return "", game.None, sn.NewVError("Only the current player may perform this action.")
+		return "", game.None, g.verror(ErrNotCurrentPlayer, "Only the current player may perform this action.")
 	}
 
 	restful.AddNoticef(c, "%s reset turn.", g.NameFor(cp))
@@ -316,7 +338,7 @@ func (g *Game) resetTurn(c *gin.Context, cu *user.User) (string, game.ActionType
 func (g *Game) redoAction(c *gin.Context, cu *user.User) (string, game.ActionType, error) {
 	cp := g.CurrentPlayer()
 	if !g.IsCurrentPlayer(cu) {
-		return "", game.None, sn.NewVError("Only the current player may perform this action.")
+		return "", game.None, g.verror(ErrNotCurrentPlayer, "Only the current player may perform this action.")
 	}
 
 	restful.AddNoticef(c, "%s redid action.", g.NameFor(cp))
@@ -0,0 +1,97 @@
+package tammany
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SlothNinja/game"
+	"github.com/gin-gonic/gin"
+)
+
+// catalogs provides the translations of messages by language.
+// Messages are identified by their English format, which serves as the translation into English.
+// Translations may reorder the arguments of a message using explicit argument indexes, e.g. %[2]d.
+var catalogs = map[string]map[string]string{
+	"de": deMessages,
+}
+
+// translate formats the message identified by msgid in the language, falling back to English for
+// unsupported languages and untranslated messages. Nationalities, offices, and phases among args are
+// replaced by their names in the language.
+func translate(lang, msgid string, args ...interface{}) string {
+	format := msgid
+	if s, ok := catalogs[lang][msgid]; ok {
+		format = s
+	}
+
+	args = append([]interface{}(nil), args...)
+	for i, arg := range args {
+		switch a := arg.(type) {
+		case nationality:
+			args[i] = translate(lang, a.String())
+		case office:
+			args[i] = translate(lang, a.String())
+		case game.Phase:
+			args[i] = translate(lang, phaseNames[a])
+		}
+	}
+
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// T formats the message identified by msgid in the language of the game's viewer.
+func (g *Game) T(msgid string, args ...interface{}) string {
+	return translate(g.language(), msgid, args...)
+}
+
+// toSentence joins ss as a sentence in the language of the game's viewer.
+func (g *Game) toSentence(ss []string) string {
+	switch l := len(ss); l {
+	case 0:
+		return ""
+	case 1:
+		return ss[0]
+	default:
+		return g.T("%s and %s", strings.Join(ss[:l-1], ", "), ss[l-1])
+	}
+}
+
+func (g *Game) language() string {
+	if g.lang == "" {
+		return defaultLanguage
+	}
+	return g.lang
+}
+
+// withLanguage provides a view of the game in the language, sharing its header and state.
+func (g *Game) withLanguage(lang string) *Game {
+	return &Game{Header: g.Header, State: g.State, lang: lang}
+}
+
+// languageFor provides the language preferred by the current user,
+// as set in their preferences or, lacking a preference, as accepted by their browser.
+func (client *Client) languageFor(c *gin.Context) string {
+	cu, err := client.User.Current(c)
+	if err == nil && cu != nil {
+		p, err := client.Prefs.GetPrefs(c, cu.ID())
+		if err == nil && supportedLanguage(p.Language) {
+			return p.Language
+		}
+	}
+	return acceptedLanguage(c.GetHeader("Accept-Language"))
+}
+
+// acceptedLanguage provides the first supported language of an Accept-Language header, or the default language.
+func acceptedLanguage(header string) string {
+	for _, s := range strings.Split(header, ",") {
+		tag := strings.TrimSpace(strings.SplitN(s, ";", 2)[0])
+		lang := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+		if supportedLanguage(lang) {
+			return lang
+		}
+	}
+	return defaultLanguage
+}
@@ -0,0 +1,245 @@
+package tammany
+
+// deMessages provides the German translations of messages.
+// Nationalities are named as in "Einwanderer (Irisch)", as their adjectives would otherwise require declension.
+var deMessages = map[string]string{
+	// Nationalities
+	"Irish":   "Irisch",
+	"English": "Englisch",
+	"German":  "Deutsch",
+	"Italian": "Italienisch",
+
+	// Offices
+	"None":              "Keins",
+	"Mayor":             "Bürgermeister",
+	"Deputy Mayor":      "Stellvertretender Bürgermeister",
+	"Council President": "Ratsvorsitzender",
+	"Chief Of Police":   "Polizeichef",
+	"Precinct Chairman": "Bezirksvorsteher",
+
+	// Phases
+	"Actions":                     "Aktionen",
+	"Place Immigrant":             "Einwanderer platzieren",
+	"Elections":                   "Wahlen",
+	"Take Favor Chip":             "Gunstchip nehmen",
+	"End Game Scoring":            "Schlusswertung",
+	"Score Victory Points":        "Siegpunktwertung",
+	"Assign City Offices":         "Ämter vergeben",
+	"Award Favor Chips":           "Gunstchips vergeben",
+	"Setup":                       "Aufbau",
+	"Castle Garden":               "Castle Garden",
+	"Announce Winners":            "Sieger verkünden",
+	"Game Over":                   "Spielende",
+	"Assign Deputy Mayor":         "Stellvertretenden Bürgermeister ernennen",
+	"Deputy Mayor Assigns Office": "Stellvertretender Bürgermeister vergibt Amt",
+
+	// Lists
+	"%s and %s": "%s und %s",
+	"%d %s":     "%d %s",
+
+	// Log entries
+	"%s assigned %s the office of %s.":                                             "%s hat %s das Amt %s übertragen.",
+	"%s received %s favor chips":                                                   "%s erhielt Gunstchips (%s)",
+	"No favor chips were awarded.":                                                 "Es wurden keine Gunstchips vergeben.",
+	"%s placed no immigrants in the Castle Garden.":                                "%s hat keine Einwanderer in den Castle Garden gesetzt.",
+	"%s placed %s immigrants in the Castle Garden.":                                "%s hat Einwanderer (%s) in den Castle Garden gesetzt.",
+	"%s won the election in ward %d.":                                              "%s hat die Wahl in Bezirk %d gewonnen.",
+	"%s had %d bosses":                                                             "%s hatte %d Bosse",
+	"%s had 1 boss":                                                                "%s hatte 1 Boss",
+	" and played %s favor chips":                                                   " und spielte Gunstchips (%s)",
+	"No bosses contested the election in ward %d.":                                 "In Bezirk %d hat kein Boss kandidiert.",
+	"The election in ward %d was a tie.":                                           "Die Wahl in Bezirk %d endete unentschieden.",
+	"%s scored 2 points for %s favor chips.":                                       "%s erhielt 2 Punkte für Gunstchips (%s).",
+	"%s scored %v points for unused slander chips.":                                "%s erhielt %v Punkte für ungenutzte Verleumdungschips.",
+	"Congratulations: %s.":                                                         "Herzlichen Glückwunsch: %s.",
+	"Victory points were scored.":                                                  "Siegpunkte wurden gewertet.",
+	"%s won 1 ward and scored %d points":                                           "%s gewann 1 Bezirk und erhielt %d Punkte",
+	"%s won %d wards and scored %d points":                                         "%s gewann %d Bezirke und erhielt %d Punkte",
+	"%s was elected mayor.":                                                        "%s wurde zum Bürgermeister gewählt.",
+	"%s locked-up ward %d.":                                                        "%s hat Bezirk %d abgeriegelt.",
+	"%s moved a %s immigrant from ward %d to ward %d.":                             "%s hat einen Einwanderer (%s) von Bezirk %d nach Bezirk %d verlegt.",
+	"placed 1 boss in ward %d":                                                     "setzte 1 Boss in Bezirk %d",
+	"placed %d bosses in ward %d":                                                  "setzte %d Bosse in Bezirk %d",
+	"placed 1 %s immigrant in ward %d":                                             "setzte 1 Einwanderer (%s) in Bezirk %d",
+	"received 1 %s favor":                                                          "erhielt 1 Gunstchip (%s)",
+	"%s placed two bosses in ward %d.":                                             "%s hat zwei Bosse in Bezirk %d gesetzt.",
+	"%s placed a boss in ward %d.":                                                 "%s hat einen Boss in Bezirk %d gesetzt.",
+	"%s removed a %s immigrant from ward %d.":                                      "%s hat einen Einwanderer (%s) aus Bezirk %d entfernt.",
+	"%s placed a %s immigrant in ward %d.":                                         "%s hat einen Einwanderer (%s) in Bezirk %d gesetzt.",
+	"%s placed a boss and a %s immigrant in ward %d.":                              "%s hat einen Boss und einen Einwanderer (%s) in Bezirk %d gesetzt.",
+	"%s took a %s favor chip.":                                                     "%s hat einen Gunstchip (%s) genommen.",
+	"%s used an %s favor to slander %s in ward %d.":                                "%[1]s hat mit einem Gunstchip (%[2]s) %[3]s in Bezirk %[4]d verleumdet.",
+	"%s used two %s favors to slander %s in ward %d.":                              "%[1]s hat mit zwei Gunstchips (%[2]s) %[3]s in Bezirk %[4]d verleumdet.",
+	"Admin %s rewound the game to entry %d, removing %d later entries. Reason: %s": "Admin %s hat das Spiel auf Eintrag %d zurückgesetzt und %d spätere Einträge entfernt. Grund: %s",
+	"%v is not a valid action.":                                                    "%v ist keine gültige Aktion.",
+	"You may still use your office. Finish again with confirm to end your turn.":   "Du kannst dein Amt noch nutzen. Beende erneut mit Bestätigung, um deinen Zug zu beenden.",
+	"Invalid area selected.":                                                       "Ungültiger Bereich ausgewählt.",
+	"The move is noted for player %d, who is not your current player.":             "Der Zug ist für Spieler %d notiert, der nicht dein aktueller Spieler ist.",
+	"The election is in ward %d, not ward %d.":                                     "Die Wahl findet in Bezirk %d statt, nicht in Bezirk %d.",
+
+	// Validation of actions
+	"Only the current player can select an office.":                         "Nur der aktuelle Spieler kann ein Amt auswählen.",
+	"Invalid office assigned.":                                              "Ungültiges Amt vergeben.",
+	"You have already performed an action.":                                 "Du hast bereits eine Aktion ausgeführt.",
+	"%s office has already been assigned.":                                  "Das Amt %s wurde bereits vergeben.",
+	"Invalid value received for office.":                                    "Ungültiger Wert für das Amt erhalten.",
+	"Invalid value received for player.":                                    "Ungültiger Wert für den Spieler erhalten.",
+	"%s has already been assigned the office of %s":                         "%s wurde bereits das Amt %s übertragen",
+	"There is no Mayor to appoint a Deputy Mayor.":                          "Es gibt keinen Bürgermeister, der einen Stellvertretenden Bürgermeister ernennen könnte.",
+	"You are not the Mayor and therefore can't assign offices.":             "Du bist nicht der Bürgermeister und kannst daher keine Ämter vergeben.",
+	"The mayor must first appoint a Deputy Mayor.":                          "Der Bürgermeister muss zuerst einen Stellvertretenden Bürgermeister ernennen.",
+	"There is no Deputy Mayor to assign offices.":                           "Es gibt keinen Stellvertretenden Bürgermeister, der Ämter vergeben könnte.",
+	"You are not the Deputy Mayor and therefore can't assign offices.":      "Du bist nicht der Stellvertretende Bürgermeister und kannst daher keine Ämter vergeben.",
+	"There is no Mayor to assign offices.":                                  "Es gibt keinen Bürgermeister, der Ämter vergeben könnte.",
+	"Only the current player can place a bid.":                              "Nur der aktuelle Spieler kann bieten.",
+	"You played %s favour chips, but there are no %s immigrants in ward %d": "Du hast Gunstchips (%s) gespielt, aber in Bezirk %[3]d gibt es keine Einwanderer (%[2]s)",
+	"Invalid value received for played %s chips.":                           "Ungültiger Wert für gespielte Chips (%s) erhalten.",
+	"You played more %s chips, than you have.":                              "Du hast mehr Chips (%s) gespielt, als du besitzt.",
+	"Only the current player can take this action.":                         "Nur der aktuelle Spieler kann diese Aktion ausführen.",
+	"Wrong phase for performing this action.":                               "Falsche Phase für diese Aktion.",
+	"Improper Phase for finishing turn.":                                    "Falsche Phase zum Beenden des Zuges.",
+	"missing stats for player.":                                             "Statistiken des Spielers fehlen.",
+	"missing current user.":                                                 "Aktueller Benutzer fehlt.",
+	"Only the current player may finish a turn.":                            "Nur der aktuelle Spieler darf einen Zug beenden.",
+	"%s has yet to perform an action.":                                      "%s hat noch keine Aktion ausgeführt.",
+	"You must complete move of %s immigrant before finishing turn.":         "Du musst das Verlegen des Einwanderers (%s) abschließen, bevor du den Zug beendest.",
+	`Expected "Actions" phase but have %q phase.`:                           `Phase "Aktionen" erwartet, aber Phase %q liegt vor.`,
+	`Expected "Elections" phase but have %q phase.`:                         `Phase "Wahlen" erwartet, aber Phase %q liegt vor.`,
+	`Expected "Place Immigrant" phase but have %q phase.`:                   `Phase "Einwanderer platzieren" erwartet, aber Phase %q liegt vor.`,
+	`Expected "Take Favor Chip" phase but have %q phase.`:                   `Phase "Gunstchip nehmen" erwartet, aber Phase %q liegt vor.`,
+	`Expected "Assign City Offices" phase but have %q phase.`:               `Phase "Ämter vergeben" erwartet, aber Phase %q liegt vor.`,
+	"You must first assign all players an office":                           "Du musst zuerst allen Spielern ein Amt übertragen",
+	"Only the current player may perform this action.":                      "Nur der aktuelle Spieler darf diese Aktion ausführen.",
+	"Only the current player can lockup a ward.":                            "Nur der aktuelle Spieler kann einen Bezirk abriegeln.",
+	"You must first select a ward.":                                         "Du musst zuerst einen Bezirk auswählen.",
+	"You can't place lockup an already locked ward.":                        "Du kannst einen bereits abgeriegelten Bezirk nicht abriegeln.",
+	"You have already lockedup a ward this year.":                           "Du hast in diesem Jahr bereits einen Bezirk abgeriegelt.",
+	"You are in the process of placing pieces (immigrants and/or bosses).  You must use office before or after placing pieces, but not during.": "Du bist dabei, Spielsteine (Einwanderer und/oder Bosse) zu setzen.  Du musst dein Amt vor oder nach dem Setzen nutzen, nicht währenddessen.",
+	"You have already lockedup two wards this term.":                                                      "Du hast in dieser Amtszeit bereits zwei Bezirke abgeriegelt.",
+	"You are the %s.  Only the Council President may lockup a ward.":                                      "Du bist %s.  Nur der Ratsvorsitzende darf einen Bezirk abriegeln.",
+	"Only the current player can move an immigrant between wards.":                                        "Nur der aktuelle Spieler kann einen Einwanderer zwischen Bezirken verlegen.",
+	"You can't move an immigrant from a locked ward.":                                                     "Du kannst keinen Einwanderer aus einem abgeriegelten Bezirk verlegen.",
+	"You must move an immigrant before or after the placing pieces action, not during.":                   "Du musst einen Einwanderer vor oder nach dem Setzen von Spielsteinen verlegen, nicht währenddessen.",
+	"You can't move an immigrant during the %s phase.":                                                    "Du kannst während der Phase %s keinen Einwanderer verlegen.",
+	"You can't move the last immigrant from the ward.":                                                    "Du kannst den letzten Einwanderer nicht aus dem Bezirk verlegen.",
+	"You are the %s.  Only the Precinct Chairman can move an immigrant between wards.":                    "Du bist %s.  Nur der Bezirksvorsteher kann einen Einwanderer zwischen Bezirken verlegen.",
+	"Only the current player can place a boss.":                                                           "Nur der aktuelle Spieler kann einen Boss setzen.",
+	"You can't move an immigrant to a locked ward.":                                                       "Du kannst keinen Einwanderer in einen abgeriegelten Bezirk verlegen.",
+	"You have already used your office power.":                                                            "Du hast die Macht deines Amtes bereits genutzt.",
+	"The Immigrant Bag does not have a %s cube to place.":                                                 "Der Einwandererbeutel enthält keinen Würfel (%s) zum Setzen.",
+	"Expected placement of %s immigrant, but received placement of %s immigrant.":                         "Setzen eines Einwanderers (%s) erwartet, aber Setzen eines Einwanderers (%s) erhalten.",
+	"Ward %d is not adjacent to ward %d.":                                                                 "Bezirk %d grenzt nicht an Bezirk %d.",
+	"You can't place pieces into a locked ward.":                                                          "Du kannst keine Spielsteine in einen abgeriegelten Bezirk setzen.",
+	"You cannot place %d bosses.":                                                                         "Du kannst keine %d Bosse setzen.",
+	"You cannot place %d pieces.":                                                                         "Du kannst keine %d Spielsteine setzen.",
+	"You already placed %d pieces.  You cannot place %d more pieces.":                                     "Du hast bereits %d Spielsteine gesetzt.  Du kannst keine %d weiteren Spielsteine setzen.",
+	"There is not a %s immigrant in the Castle Garden":                                                    "Im Castle Garden gibt es keinen Einwanderer (%s)",
+	"You already placed %d immigrants.  You cannot place another immigrant.":                              "Du hast bereits %d Einwanderer gesetzt.  Du kannst keinen weiteren Einwanderer setzen.",
+	"You cannot place a boss.":                                                                            "Du kannst keinen Boss setzen.",
+	"You must place 1 immigrant.":                                                                         "Du musst 1 Einwanderer setzen.",
+	"You selected an invalid nationality.":                                                                "Du hast eine ungültige Nationalität ausgewählt.",
+	"Only the current player can remove an immigrant from a ward.":                                        "Nur der aktuelle Spieler kann einen Einwanderer aus einem Bezirk entfernen.",
+	"You can't remove an immigrant from a locked ward.":                                                   "Du kannst keinen Einwanderer aus einem abgeriegelten Bezirk entfernen.",
+	"You must remove an immigrant before or after the placing pieces action, not during.":                 "Du musst einen Einwanderer vor oder nach dem Setzen von Spielsteinen entfernen, nicht währenddessen.",
+	"You can't remove an immigrant during the %s phase.":                                                  "Du kannst während der Phase %s keinen Einwanderer entfernen.",
+	"You can't remove the last immigrant from the ward.":                                                  "Du kannst den letzten Einwanderer nicht aus dem Bezirk entfernen.",
+	"You are the %s.  Only the Chief of Police can remove an immigrant from the ward.":                    "Du bist %s.  Nur der Polizeichef kann einen Einwanderer aus dem Bezirk entfernen.",
+	"Invalid value received for chip nationatlity.":                                                       "Ungültiger Wert für die Nationalität des Chips erhalten.",
+	"Only the current player can take a chip.":                                                            "Nur der aktuelle Spieler kann einen Chip nehmen.",
+	"You have already taken a favor chip.":                                                                "Du hast bereits einen Gunstchip genommen.",
+	"You can't take a favour chip in phase %q.":                                                           "Du kannst in der Phase %q keinen Gunstchip nehmen.",
+	"You are the %s.  Only the Deputy Mayor may take a favor chip.":                                       "Du bist %s.  Nur der Stellvertretende Bürgermeister darf einen Gunstchip nehmen.",
+	"you must select a chip to play":                                                                      "Du musst einen Chip zum Spielen auswählen",
+	"Only the current player can slander another player.":                                                 "Nur der aktuelle Spieler kann einen anderen Spieler verleumden.",
+	"You must select a favor chip with which to slandar.":                                                 "Du musst einen Gunstchip zum Verleumden auswählen.",
+	"You can't slander a player in locked ward.":                                                          "Du kannst keinen Spieler in einem abgeriegelten Bezirk verleumden.",
+	"You attempted to slander with a %s chip, but there are no %s immigrants in the selected ward.":       "Du wolltest mit einem Chip (%s) verleumden, aber im ausgewählten Bezirk gibt es keine Einwanderer (%s).",
+	"You can't slander in term %d.":                                                                       "Du kannst in Amtszeit %d nicht verleumden.",
+	"You don't have a %s favor to use for the slander.":                                                   "Du hast keinen Gunstchip (%s) für die Verleumdung.",
+	"You don't have two %s favors to use for the second slander.":                                         "Du hast keine zwei Gunstchips (%s) für die zweite Verleumdung.",
+	"You must select a player to slander.":                                                                "Du musst einen Spieler zum Verleumden auswählen.",
+	"You can't slander yourself.":                                                                         "Du kannst dich nicht selbst verleumden.",
+	"You attempted to slander %s, but you are in the process or slandering %s.":                           "Du wolltest %s verleumden, verleumdest aber gerade %s.",
+	"You attempted to slander using %s favors, but you are in the process or slandering using %s favors.": "Du wolltest mit Gunstchips (%s) verleumden, verleumdest aber gerade mit Gunstchips (%s).",
+	"You have already slandered twice this term.":                                                         "Du hast in dieser Amtszeit bereits zweimal verleumdet.",
+	"You have already slandered this term.":                                                               "Du hast in dieser Amtszeit bereits verleumdet.",
+	"Only the current player can consider a bid.":                                                         "Nur der aktuelle Spieler kann ein Gebot erwägen.",
+	"There is no election in progress.":                                                                   "Es findet keine Wahl statt.",
+	"You are not a candidate in ward %d.":                                                                 "Du kandidierst nicht in Bezirk %d.",
+
+	// Notation of moves
+	"Missing move in %q.":                             "Kein Zug in %q.",
+	"Unexpected arguments %q for %s.":                 "Unerwartete Argumente %q für %s.",
+	"Invalid year %q.":                                "Ungültiges Jahr %q.",
+	"Expected a ward or office to select.":            "Bezirk oder Amt zur Auswahl erwartet.",
+	"Expected an office and player to assign.":        "Amt und Spieler zur Vergabe erwartet.",
+	"Invalid move %q.":                                "Ungültiger Zug %q.",
+	"Invalid number of bosses %q.":                    "Ungültige Anzahl Bosse %q.",
+	"Expected an immigrant to remove, e.g. Irish@W6.": "Einwanderer zum Entfernen erwartet, z. B. Irish@W6.",
+	"Expected MV Irish@W6 -> or MV -> Irish@W7.":      "MV Irish@W6 -> oder MV -> Irish@W7 erwartet.",
+	"Expected a ward to lock up.":                     "Bezirk zum Abriegeln erwartet.",
+	"Expected a favor chip to take.":                  "Gunstchip zum Nehmen erwartet.",
+	"Expected SL <ward> <chip> -> <player>.":          "SL <Bezirk> <Chip> -> <Spieler> erwartet.",
+	"Expected the ward of the election.":              "Bezirk der Wahl erwartet.",
+	"Invalid bid %q.":                                 "Ungültiges Gebot %q.",
+	"Invalid number of chips %q.":                     "Ungültige Anzahl Chips %q.",
+	"Expected an admin action.":                       "Admin-Aktion erwartet.",
+	"Invalid admin value %q.":                         "Ungültiger Admin-Wert %q.",
+	"Missing ward in %q.":                             "Kein Bezirk in %q.",
+	"Invalid ward %q.":                                "Ungültiger Bezirk %q.",
+	"Invalid player %q.":                              "Ungültiger Spieler %q.",
+	"Invalid office %q.":                              "Ungültiges Amt %q.",
+	"Invalid nationality %q.":                         "Ungültige Nationalität %q.",
+	"Invalid values for %s: %v":                       "Ungültige Werte für %s: %v",
+
+	// Admin actions
+	"Only an admin may edit the game.":                            "Nur ein Admin darf das Spiel bearbeiten.",
+	"Only an admin may rewind the game.":                          "Nur ein Admin darf das Spiel zurücksetzen.",
+	"Only a running game may be rewound.":                         "Nur ein laufendes Spiel kann zurückgesetzt werden.",
+	"A reason is required to rewind the game.":                    "Zum Zurücksetzen des Spiels ist ein Grund erforderlich.",
+	"Unable to rewind to entry %d, since the log has %d entries.": "Zurücksetzen auf Eintrag %d nicht möglich, da das Protokoll %d Einträge hat.",
+	"No saved state exists for entry %d.":                         "Für Eintrag %d existiert kein gespeicherter Zustand.",
+	"Player %d does not exist.":                                   "Spieler %d existiert nicht.",
+	"Ward %d does not exist.":                                     "Bezirk %d existiert nicht.",
+	"Expected %d chip and played chip counts.":                    "%d Anzahlen von Chips und gespielten Chips erwartet.",
+	"Expected %d boss counts.":                                    "%d Anzahlen von Bossen erwartet.",
+	"Invalid edit: %v":                                            "Ungültige Bearbeitung: %v",
+	"Invalid edits: %v":                                           "Ungültige Bearbeitungen: %v",
+	"Invalid value received for edits.":                           "Ungültiger Wert für die Bearbeitungen erhalten.",
+	"Invalid value received for entry.":                           "Ungültiger Wert für den Eintrag erhalten.",
+	"Entry %d is not an admin edit.":                              "Eintrag %d ist keine Admin-Bearbeitung.",
+	"Entry %d was already rolled back.":                           "Eintrag %d wurde bereits rückgängig gemacht.",
+	"Unable to roll back entry %d: %v":                            "Eintrag %d kann nicht rückgängig gemacht werden: %v",
+	"Entry %d needs no rollback, since its target is unchanged since before the edit.": "Eintrag %d muss nicht rückgängig gemacht werden, da sein Ziel seit der Bearbeitung unverändert ist.",
+	"the game state":    "den Spielzustand",
+	"ward %d":           "Bezirk %d",
+	"the Castle Garden": "den Castle Garden",
+	"the immigrant bag": "den Einwandererbeutel",
+	"the password":      "das Passwort",
+	"%s from %s to %s":  "%s von %s auf %s",
+	"Admin %s edited %s, changing %s. Reason: %s":      "Admin %s hat %s bearbeitet und %s geändert. Grund: %s",
+	"Admin %s rolled back %s, changing %s. Reason: %s": "Admin %s hat die Bearbeitung von %s rückgängig gemacht und %s geändert. Grund: %s",
+
+	// Boards
+	"Recruiting":           "Spielersuche",
+	"Running":              "Läuft",
+	"Completed":            "Beendet",
+	"Abandoned":            "Verlassen",
+	"Aborted":              "Abgebrochen",
+	"Year %d | %s | %s":    "Jahr %d | %s | %s",
+	"Current: %s":          "Am Zug: %s",
+	"Moving: %s immigrant": "Wird verlegt: Einwanderer (%s)",
+	"Bag":                  "Beutel",
+	"Offices: %s":          "Ämter: %s",
+	"Ward":                 "Bezirk",
+	"Bosses":               "Bosse",
+	"LOCKED UP":            "ABGERIEGELT",
+	"(> current ward, L locked up, R resolved)": "(> aktueller Bezirk, L abgeriegelt, R entschieden)",
+	"Player":        "Spieler",
+	"Color":         "Farbe",
+	"Score":         "Punkte",
+	"Office":        "Amt",
+	"Favor Chips":   "Gunstchips",
+	"Slander Chips": "Verleumdungschips",
+	"(* current player; slander chips by term)": "(* aktueller Spieler; Verleumdungschips nach Amtszeit)",
+}
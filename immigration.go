@@ -49,7 +49,7 @@ func (g *Game) zone1Immigration() {
 		case 14:
 			ward.Immigrants[irish]++
 		default:
-			ward.Immigrants[immigrants.draw()]++
+			ward.Immigrants[g.drawFrom(immigrants)]++
 		}
 	}
 }
@@ -57,13 +57,13 @@ func (g *Game) zone1Immigration() {
 func (g *Game) zone2Immigration() {
 	immigrants := defaultZone2Immigrants()
 	for _, ward := range g.Zone2Wards() {
-		ward.Immigrants[immigrants.draw()]++
+		ward.Immigrants[g.drawFrom(immigrants)]++
 	}
 }
 
 func (g *Game) zone3Immigration() {
 	immigrants := defaultZone3Immigrants()
 	for _, ward := range g.Zone3Wards() {
-		ward.Immigrants[immigrants.draw()]++
+		ward.Immigrants[g.drawFrom(immigrants)]++
 	}
 }
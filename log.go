@@ -25,6 +25,12 @@ type Entryer interface {
 	Round() int
 	CreatedAt() time.Time
 	HTML(*gin.Context, *Game, *user.User) template.HTML
+
+	// Text renders the entry as plain text, without requiring a request context or templates.
+	Text(*Game) string
+
+	// Event provides a typed view of the entry.
+	Event() *Event
 }
 
 func (g *Game) newEntry() (e *Entry) {
@@ -0,0 +1,121 @@
+package tammany
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	markdownFormat = "md"
+	textFormat     = "txt"
+)
+
+// logSection groups consecutive log entries sharing a phase name.
+type logSection struct {
+	Heading string
+	Lines   []string
+}
+
+func (g *Game) logSections() []*logSection {
+	var (
+		ss []*logSection
+		s  *logSection
+	)
+
+	for _, e := range g.Log {
+		if heading := e.PhaseName(); s == nil || s.Heading != heading {
+			s = &logSection{Heading: heading}
+			ss = append(ss, s)
+		}
+		s.Lines = append(s.Lines, e.Text(g))
+	}
+	return ss
+}
+
+// LogMarkdown renders the game log as Markdown, with a heading for each year and phase.
+func (g *Game) LogMarkdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s (Game %d)\n", escapeMarkdown(g.Title), g.ID())
+	for _, s := range g.logSections() {
+		fmt.Fprintf(&b, "\n## %s\n\n", s.Heading)
+		for _, line := range s.Lines {
+			fmt.Fprintf(&b, "- %s\n", escapeMarkdown(line))
+		}
+	}
+	return b.String()
+}
+
+// LogText renders the game log as plain text, with a heading for each year and phase.
+func (g *Game) LogText() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (Game %d)\n", g.Title, g.ID())
+	for _, s := range g.logSections() {
+		fmt.Fprintf(&b, "\n%s\n", s.Heading)
+		for _, line := range s.Lines {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+	return b.String()
+}
+
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+	"#", `\#`,
+	"<", `\<`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownReplacer.Replace(s)
+}
+
+// exportLog renders the game log as Markdown or, when requested using format=txt, as plain text.
+func (client *Client) exportLog(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		id, err := getID(c)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.Redirect(http.StatusSeeOther, homePath)
+			return
+		}
+
+		g := New(c, id)
+		err = client.dsGet(c, g)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.Redirect(http.StatusSeeOther, homePath)
+			return
+		}
+
+		cu, err := client.User.Current(c)
+		if err != nil {
+			client.Log.Debugf(err.Error())
+		}
+
+		g, err = client.spectatorView(c, g, cu)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.String(http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		switch format := c.DefaultQuery("format", markdownFormat); format {
+		case markdownFormat:
+			c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(g.LogMarkdown()))
+		case textFormat:
+			c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(g.LogText()))
+		default:
+			c.String(http.StatusBadRequest, "unsupported log format %q", format)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package tammany
+
+import (
+	"sort"
+	"sync"
+
+	"cloud.google.com/go/datastore"
+	"github.com/SlothNinja/codec"
+	"github.com/SlothNinja/game"
+	"github.com/SlothNinja/mlog"
+	"github.com/gin-gonic/gin"
+)
+
+// memoryStore provides an in-memory GameStore for local development and tests.
+// Games are stored gob encoded, so loaded games never share state with stored games.
+type memoryStore struct {
+	mu        sync.Mutex
+	lastID    int64
+	games     map[int64][]byte
+	entities  map[string][]byte
+	snapshots map[int64]map[int]*Snapshot
+}
+
+// NewMemoryStore returns a GameStore that keeps games in memory.
+func NewMemoryStore() GameStore {
+	return &memoryStore{
+		games:     make(map[int64][]byte),
+		entities:  make(map[string][]byte),
+		snapshots: make(map[int64]map[int]*Snapshot),
+	}
+}
+
+func (s *memoryStore) Get(c *gin.Context, g *Game) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bs, ok := s.games[g.ID()]
+	if !ok {
+		return ErrGameNotFound
+	}
+	return codec.Decode(g.Header, bs)
+}
+
+func (s *memoryStore) Put(c *gin.Context, g *Game, ks []*datastore.Key, es []interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bs, ok := s.games[g.ID()]
+	if !ok {
+		return ErrGameNotFound
+	}
+
+	oldG := New(c, g.ID())
+	err := codec.Decode(oldG.Header, bs)
+	if err != nil {
+		return err
+	}
+
+	if !oldG.UpdatedAt.Equal(g.UpdatedAt) {
+		return ErrGameChanged
+	}
+
+	encoded := make(map[string][]byte, len(es))
+	for i, e := range es {
+		v, err := codec.Encode(e)
+		if err != nil {
+			return err
+		}
+		encoded[ks[i].Encode()] = v
+	}
+
+	err = s.put(g)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range encoded {
+		s.entities[k] = v
+	}
+	return nil
+}
+
+func (s *memoryStore) Create(c *gin.Context, g *Game, ms []*mlog.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	g.Key = newKey(c, s.lastID)
+
+	ml := mlog.New(g.ID())
+	ml.Messages = ms
+	err := s.putEntity(ml)
+	if err != nil {
+		return err
+	}
+	return s.put(g)
+}
+
+func (s *memoryStore) GetMLog(c *gin.Context, id int64) (*mlog.MLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ml := mlog.New(id)
+	bs, ok := s.entities[ml.Key.Encode()]
+	if !ok {
+		return nil, mlog.ErrNotFound
+	}
+	err := codec.Decode(ml, bs)
+	return ml, err
+}
+
+func (s *memoryStore) PutMLog(c *gin.Context, ml *mlog.MLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.putEntity(ml)
+}
+
+// putEntity saves the message log, presuming caller holds lock.
+func (s *memoryStore) putEntity(ml *mlog.MLog) error {
+	bs, err := codec.Encode(ml)
+	if err != nil {
+		return err
+	}
+	s.entities[ml.Key.Encode()] = bs
+	return nil
+}
+
+// put presumes caller holds lock.
+func (s *memoryStore) put(g *Game) error {
+	touch(g.Header)
+	bs, err := codec.Encode(g.Header)
+	if err != nil {
+		return err
+	}
+	s.games[g.ID()] = bs
+	return nil
+}
+
+func (s *memoryStore) ListByStatus(c *gin.Context, status game.Status) (Games, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var gs Games
+	for id, bs := range s.games {
+		g := New(c, id)
+		err := codec.Decode(g.Header, bs)
+		if err != nil {
+			return nil, err
+		}
+		if g.Status == status {
+			gs = append(gs, g)
+		}
+	}
+	sort.Sort(byUpdatedAt{gs})
+	return gs, nil
+}
+
+func (s *memoryStore) ListByUser(c *gin.Context, status game.Status, uid int64) (Games, error) {
+	gs, err := s.ListByStatus(c, status)
+	if err != nil {
+		return nil, err
+	}
+
+	var ugs Games
+	for _, g := range gs {
+		if hasUser(g, uid) {
+			ugs = append(ugs, g)
+		}
+	}
+	return ugs, nil
+}
+
+func (s *memoryStore) PutSnapshot(c *gin.Context, g *Game, snap *Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snaps, ok := s.snapshots[g.ID()]
+	if !ok {
+		snaps = make(map[int]*Snapshot)
+		s.snapshots[g.ID()] = snaps
+	}
+	snap2 := *snap
+	snaps[snap.LogLength] = &snap2
+	return nil
+}
+
+func (s *memoryStore) Snapshots(c *gin.Context, g *Game) ([]*Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var snaps []*Snapshot
+	for _, snap := range s.snapshots[g.ID()] {
+		snap2 := *snap
+		snaps = append(snaps, &snap2)
+	}
+	sort.Sort(bySnapshotLogLength(snaps))
+	return snaps, nil
+}
+
+func (s *memoryStore) DeleteSnapshots(c *gin.Context, g *Game, logLength int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for l := range s.snapshots[g.ID()] {
+		if l > logLength {
+			delete(s.snapshots[g.ID()], l)
+		}
+	}
+	return nil
+}
+
+// byUpdatedAt implements sort.Interface for sorting games by most recently updated.
+type byUpdatedAt struct{ Games }
+
+func (gs byUpdatedAt) Len() int      { return len(gs.Games) }
+func (gs byUpdatedAt) Swap(i, j int) { gs.Games[i], gs.Games[j] = gs.Games[j], gs.Games[i] }
+func (gs byUpdatedAt) Less(i, j int) bool {
+	return gs.Games[i].UpdatedAt.After(gs.Games[j].UpdatedAt)
+}
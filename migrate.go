@@ -0,0 +1,91 @@
+package tammany
+
+import (
+	"net/http"
+
+	"github.com/SlothNinja/game"
+	"github.com/gin-gonic/gin"
+)
+
+// migrationStatuses identifies the statuses of games upgraded by migrateStates.
+var migrationStatuses = []game.Status{
+	game.Recruiting,
+	game.Running,
+	game.Completed,
+	game.Abandoned,
+	game.Aborted,
+}
+
+type migrationFailure struct {
+	ID    int64  `json:"id"`
+	Error string `json:"error"`
+}
+
+type migrationSummary struct {
+	SchemaVersion int                `json:"schemaVersion"`
+	Checked       int                `json:"checked"`
+	Migrated      int                `json:"migrated"`
+	Failed        []migrationFailure `json:"failed"`
+}
+
+// migrateStates upgrades the saved state of every stored game to the current schema version.
+// Games already at the current schema version are left untouched, so the migration may safely be rerun.
+func (client *Client) migrateStates(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil || !cu.IsAdmin() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only an admin may migrate games."})
+			return
+		}
+
+		summary := migrationSummary{SchemaVersion: stateSchemaVersion, Failed: []migrationFailure{}}
+		for _, status := range migrationStatuses {
+			gs, err := client.Store.ListByStatus(c, status)
+			if err != nil {
+				client.Log.Errorf(err.Error())
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "summary": summary})
+				return
+			}
+
+			for _, g := range gs {
+				summary.Checked++
+				migrated, err := client.migrateState(c, g)
+				switch {
+				case err != nil:
+					client.Log.Warningf("unable to migrate game %d: %v", g.ID(), err)
+					summary.Failed = append(summary.Failed, migrationFailure{ID: g.ID(), Error: err.Error()})
+				case migrated:
+					summary.Migrated++
+				}
+			}
+		}
+		c.JSON(http.StatusOK, summary)
+	}
+}
+
+// migrateState re-encodes the saved state of game g, provided it was saved using a prior schema version.
+// migrateState reports whether g was migrated.
+func (client *Client) migrateState(c *gin.Context, g *Game) (bool, error) {
+	s, version, err := decodeState(g.SavedState)
+	if err != nil {
+		return false, err
+	}
+
+	if version == stateSchemaVersion {
+		return false, nil
+	}
+
+	g.SavedState, err = encodeState(s)
+	if err != nil {
+		return false, err
+	}
+
+	err = client.Store.Put(c, g, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,762 @@
+package tammany
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Move provides a single player action in text notation.
+//
+// The notation of a move consists of an optional context, naming the year and player,
+// followed by the action.  For example:
+//
+//	Y3 P2: B2+Irish@W6      place two bosses and an Irish immigrant in ward 6
+//	SL W5 Ger -> P3         slander player 3 in ward 5 using a German favor chip
+//	BID W14 Irish:2 Ger:1   play two Irish and one German favor chip in the ward 14 election
+//	ASSIGN DM P4            assign the office of deputy mayor to player 4
+//
+// Players are numbered from one, so P1 denotes the player having id 0.
+type Move struct {
+	Year          int
+	PlayerID      int
+	Action        string
+	WardID        wardID
+	Office        office
+	Bosses        int
+	Immigrant     nationality
+	Chip          nationality
+	OtherPlayerID int
+	Bids          Chips
+	Params        url.Values
+}
+
+// Actions having a notation.
+const (
+	selectAreaAction     = "select-area"
+	assignOfficeAction   = "assign-office"
+	placePiecesAction    = "place-pieces"
+	removeAction         = "remove"
+	moveFromAction       = "move-from"
+	moveToAction         = "move-to"
+	lockupAction         = "place-lockup-marker"
+	deputyTakeChipAction = "deputy-take-chip"
+	takeChipAction       = "take-chip"
+	slanderAction        = "slander"
+	bidAction            = "bid"
+	undoAction           = "undo"
+	redoAction           = "redo"
+	resetAction          = "reset"
+	cancelFinishAction   = "cancel-finish"
+	finishAction         = "finish"
+)
+
+// adminActions maps the admin actions, which are noted with their form values, to the keys of the values noted.
+// Other values, such as the password and the users of the game, are never noted, lest the actions reveal them.
+var adminActions = map[string][]string{
+	"game-state":          {"reason", "title", "phase", "round", "num-players", "cp-user-indices", "winner-ids", "status"},
+	"player":              {"reason", "idf", "score", "chips", "played-chips", "performed-action", "candidate", "used-office", "placed-bosses", "placed-immigrants", "has-bid"},
+	"ward":                {"reason", "ward-id", "Irish", "English", "German", "Italian", "bosses", "resolved", "lockedup"},
+	"castle-garden":       {"reason", "Irish", "English", "German", "Italian"},
+	"immigrant-bag":       {"reason", "Irish", "English", "German", "Italian"},
+	"admin-edits":         {"reason", "edits"},
+	"rollback-admin-edit": {"reason", "entry"},
+}
+
+// simpleMoves maps the keyword of actions lacking arguments to the action.
+var simpleMoves = map[string]string{
+	"UNDO":   undoAction,
+	"REDO":   redoAction,
+	"RESET":  resetAction,
+	"CANCEL": cancelFinishAction,
+	"FINISH": finishAction,
+}
+
+var officeAbbrs = map[office]string{
+	mayor:            "M",
+	deputyMayor:      "DM",
+	councilPresident: "CP",
+	chiefOfPolice:    "COP",
+	precinctChairman: "PC",
+}
+
+var nationalityAbbrs = map[nationality]string{
+	irish:   "Irish",
+	english: "Eng",
+	german:  "Ger",
+	italian: "Ita",
+}
+
+// Abbr provides the abbreviation of the nationality used in move notation.
+func (n nationality) Abbr() string {
+	return nationalityAbbrs[n]
+}
+
+// Abbr provides the abbreviation of the office used in move notation.
+func (o office) Abbr() string {
+	return officeAbbrs[o]
+}
+
+func newMove(action string) *Move {
+	return &Move{
+		PlayerID:      noPlayerID,
+		Action:        action,
+		WardID:        noWardID,
+		OtherPlayerID: noPlayerID,
+	}
+}
+
+// ParseMove parses the text notation of a move.
+func ParseMove(s string) (*Move, error) {
+	m := newMove("")
+
+	body := strings.TrimSpace(s)
+	if i := strings.Index(body, ":"); i != -1 && isMoveContext(body[:i]) {
+		err := m.parseContext(body[:i])
+		if err != nil {
+			return nil, err
+		}
+		body = strings.TrimSpace(body[i+1:])
+	}
+
+	fields := strings.Fields(body)
+	if len(fields) == 0 {
+		return nil, invalidMove("Missing move in %q.", s)
+	}
+
+	var err error
+	switch keyword, args := strings.ToUpper(fields[0]), fields[1:]; keyword {
+	case "SEL":
+		err = m.parseSelect(args)
+	case "ASSIGN":
+		err = m.parseAssign(args)
+	case "RM":
+		err = m.parseRemove(args)
+	case "MV":
+		err = m.parseMoveImmigrant(args)
+	case "LOCK":
+		err = m.parseLockup(args)
+	case "TAKE", "DTAKE":
+		err = m.parseTakeChip(keyword, args)
+	case "SL":
+		err = m.parseSlander(args)
+	case "BID":
+		err = m.parseBid(args)
+	case "ADMIN":
+		err = m.parseAdmin(args)
+	default:
+		if action, ok := simpleMoves[keyword]; ok {
+			if len(args) != 0 {
+				return nil, invalidMove("Unexpected arguments %q for %s.", strings.Join(args, " "), keyword)
+			}
+			m.Action = action
+			break
+		}
+		err = m.parsePlacePieces(fields)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ParseMoves parses moves, one per line, ignoring blank lines and lines beginning with '#'.
+func ParseMoves(s string) ([]*Move, error) {
+	var ms []*Move
+	for i, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		m, err := ParseMove(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+func isMoveContext(s string) bool {
+	for _, f := range strings.Fields(s) {
+		switch f[0] {
+		case 'Y', 'y', 'P', 'p':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Move) parseContext(s string) error {
+	for _, f := range strings.Fields(s) {
+		switch f[0] {
+		case 'Y', 'y':
+			y, err := strconv.Atoi(f[1:])
+			if err != nil || y < 1 {
+				return invalidMove("Invalid year %q.", f)
+			}
+			m.Year = y
+		default:
+			pid, err := parsePlayer(f)
+			if err != nil {
+				return err
+			}
+			m.PlayerID = pid
+		}
+	}
+	return nil
+}
+
+func (m *Move) parseSelect(args []string) error {
+	m.Action = selectAreaAction
+	if len(args) != 1 {
+		return invalidMove("Expected a ward or office to select.")
+	}
+
+	if o, err := parseOffice(args[0]); err == nil {
+		m.Office = o
+		return nil
+	}
+
+	w, err := parseWard(args[0])
+	if err != nil {
+		return err
+	}
+	m.WardID = w
+	return nil
+}
+
+func (m *Move) parseAssign(args []string) error {
+	m.Action = assignOfficeAction
+	if len(args) != 2 {
+		return invalidMove("Expected an office and player to assign.")
+	}
+
+	o, err := parseOffice(args[0])
+	if err != nil {
+		return err
+	}
+
+	pid, err := parsePlayer(args[1])
+	if err != nil {
+		return err
+	}
+	m.Office, m.OtherPlayerID = o, pid
+	return nil
+}
+
+// parsePlacePieces parses placements of the form B2+Irish@W6, B1@W6, or Irish@W6.
+func (m *Move) parsePlacePieces(fields []string) error {
+	m.Action = placePiecesAction
+	if len(fields) != 1 {
+		return invalidMove("Invalid move %q.", strings.Join(fields, " "))
+	}
+
+	pieces, w, err := splitAt(fields[0])
+	if err != nil {
+		return err
+	}
+	m.WardID = w
+
+	for _, piece := range strings.Split(pieces, "+") {
+		if len(piece) > 1 && (piece[0] == 'B' || piece[0] == 'b') {
+			if b, err := strconv.Atoi(piece[1:]); err == nil {
+				if b < 0 {
+					return invalidMove("Invalid number of bosses %q.", piece)
+				}
+				m.Bosses = b
+				continue
+			}
+		}
+
+		n, err := parseNationality(piece)
+		if err != nil {
+			return err
+		}
+		m.Immigrant = n
+	}
+	return nil
+}
+
+func (m *Move) parseRemove(args []string) error {
+	m.Action = removeAction
+	if len(args) != 1 {
+		return invalidMove("Expected an immigrant to remove, e.g. Irish@W6.")
+	}
+	return m.parseImmigrantAt(args[0])
+}
+
+// parseMoveImmigrant parses the two steps of moving an immigrant: MV Irish@W6 -> and MV -> Irish@W7.
+func (m *Move) parseMoveImmigrant(args []string) error {
+	switch {
+	case len(args) == 2 && args[1] == "->":
+		m.Action = moveFromAction
+		return m.parseImmigrantAt(args[0])
+	case len(args) == 2 && args[0] == "->":
+		m.Action = moveToAction
+		return m.parseImmigrantAt(args[1])
+	default:
+		return invalidMove("Expected MV Irish@W6 -> or MV -> Irish@W7.")
+	}
+}
+
+func (m *Move) parseImmigrantAt(s string) error {
+	piece, w, err := splitAt(s)
+	if err != nil {
+		return err
+	}
+
+	n, err := parseNationality(piece)
+	if err != nil {
+		return err
+	}
+	m.Immigrant, m.WardID = n, w
+	return nil
+}
+
+func (m *Move) parseLockup(args []string) error {
+	m.Action = lockupAction
+	if len(args) != 1 {
+		return invalidMove("Expected a ward to lock up.")
+	}
+
+	w, err := parseWard(args[0])
+	if err != nil {
+		return err
+	}
+	m.WardID = w
+	return nil
+}
+
+func (m *Move) parseTakeChip(keyword string, args []string) error {
+	m.Action = takeChipAction
+	if keyword == "DTAKE" {
+		m.Action = deputyTakeChipAction
+	}
+
+	if len(args) != 1 {
+		return invalidMove("Expected a favor chip to take.")
+	}
+
+	n, err := parseNationality(args[0])
+	if err != nil {
+		return err
+	}
+	m.Chip = n
+	return nil
+}
+
+func (m *Move) parseSlander(args []string) error {
+	m.Action = slanderAction
+	if len(args) != 4 || args[2] != "->" {
+		return invalidMove("Expected SL <ward> <chip> -> <player>.")
+	}
+
+	w, err := parseWard(args[0])
+	if err != nil {
+		return err
+	}
+
+	n, err := parseNationality(args[1])
+	if err != nil {
+		return err
+	}
+
+	pid, err := parsePlayer(args[3])
+	if err != nil {
+		return err
+	}
+	m.WardID, m.Chip, m.OtherPlayerID = w, n, pid
+	return nil
+}
+
+func (m *Move) parseBid(args []string) error {
+	m.Action = bidAction
+	if len(args) == 0 {
+		return invalidMove("Expected the ward of the election.")
+	}
+
+	w, err := parseWard(args[0])
+	if err != nil {
+		return err
+	}
+	m.WardID = w
+
+	m.Bids = make(Chips)
+	for _, arg := range args[1:] {
+		ss := strings.SplitN(arg, ":", 2)
+		if len(ss) != 2 {
+			return invalidMove("Invalid bid %q.", arg)
+		}
+
+		n, err := parseNationality(ss[0])
+		if err != nil {
+			return err
+		}
+
+		count, err := strconv.Atoi(ss[1])
+		if err != nil || count < 0 {
+			return invalidMove("Invalid number of chips %q.", arg)
+		}
+		m.Bids[n] += count
+	}
+	return nil
+}
+
+// parseAdmin parses admin moves of the form ADMIN <action> key=value ..., with url query escaped values.
+func (m *Move) parseAdmin(args []string) error {
+	if len(args) == 0 || adminActions[args[0]] == nil {
+		return invalidMove("Expected an admin action.")
+	}
+	m.Action = args[0]
+
+	// Values not noted for the action are dropped, should earlier notation have noted them.
+	noted := make(map[string]bool)
+	for _, k := range adminActions[m.Action] {
+		noted[k] = true
+	}
+
+	m.Params = make(url.Values)
+	for _, arg := range args[1:] {
+		ss := strings.SplitN(arg, "=", 2)
+		if len(ss) != 2 {
+			return invalidMove("Invalid admin value %q.", arg)
+		}
+
+		k, err := url.QueryUnescape(ss[0])
+		if err != nil {
+			return invalidMove("Invalid admin value %q.", arg)
+		}
+
+		v, err := url.QueryUnescape(ss[1])
+		if err != nil {
+			return invalidMove("Invalid admin value %q.", arg)
+		}
+		if noted[k] {
+			m.Params.Add(k, v)
+		}
+	}
+	return nil
+}
+
+func splitAt(s string) (string, wardID, error) {
+	i := strings.LastIndex(s, "@")
+	if i == -1 {
+		return "", noWardID, invalidMove("Missing ward in %q.", s)
+	}
+
+	w, err := parseWard(s[i+1:])
+	if err != nil {
+		return "", noWardID, err
+	}
+	return s[:i], w, nil
+}
+
+func parseWard(s string) (wardID, error) {
+	if len(s) > 1 && (s[0] == 'W' || s[0] == 'w') {
+		if _, ok := toWardID["ward-"+s[1:]]; ok {
+			id, _ := strconv.Atoi(s[1:])
+			return wardID(id), nil
+		}
+	}
+	return noWardID, invalidMove("Invalid ward %q.", s)
+}
+
+func parsePlayer(s string) (int, error) {
+	if len(s) > 1 && (s[0] == 'P' || s[0] == 'p') {
+		if n, err := strconv.Atoi(s[1:]); err == nil && n > 0 {
+			return n - 1, nil
+		}
+	}
+	return noPlayerID, invalidMove("Invalid player %q.", s)
+}
+
+func parseOffice(s string) (office, error) {
+	for o, abbr := range officeAbbrs {
+		if strings.EqualFold(s, abbr) {
+			return o, nil
+		}
+	}
+	return noOffice, invalidMove("Invalid office %q.", s)
+}
+
+// parseNationality accepts the abbreviation or the full name of a nationality.
+func parseNationality(s string) (nationality, error) {
+	for n, abbr := range nationalityAbbrs {
+		if strings.EqualFold(s, abbr) || strings.EqualFold(s, n.String()) {
+			return n, nil
+		}
+	}
+	return noNationality, invalidMove("Invalid nationality %q.", s)
+}
+
+func formatWard(w wardID) string {
+	return fmt.Sprintf("W%d", w)
+}
+
+func formatPlayer(pid int) string {
+	return fmt.Sprintf("P%d", pid+1)
+}
+
+// String provides the text notation of the move.
+func (m *Move) String() string {
+	var context []string
+	if m.Year > 0 {
+		context = append(context, fmt.Sprintf("Y%d", m.Year))
+	}
+	if m.PlayerID != noPlayerID {
+		context = append(context, formatPlayer(m.PlayerID))
+	}
+
+	body := m.body()
+	if len(context) == 0 {
+		return body
+	}
+	return strings.Join(context, " ") + ": " + body
+}
+
+func (m *Move) body() string {
+	switch m.Action {
+	case selectAreaAction:
+		if m.Office != noOffice {
+			return "SEL " + m.Office.Abbr()
+		}
+		return "SEL " + formatWard(m.WardID)
+	case assignOfficeAction:
+		return fmt.Sprintf("ASSIGN %s %s", m.Office.Abbr(), formatPlayer(m.OtherPlayerID))
+	case placePiecesAction:
+		var pieces []string
+		if m.Bosses > 0 {
+			pieces = append(pieces, fmt.Sprintf("B%d", m.Bosses))
+		}
+		if m.Immigrant != noNationality {
+			pieces = append(pieces, m.Immigrant.Abbr())
+		}
+		if len(pieces) == 0 {
+			pieces = append(pieces, "B0")
+		}
+		return fmt.Sprintf("%s@%s", strings.Join(pieces, "+"), formatWard(m.WardID))
+	case removeAction:
+		return fmt.Sprintf("RM %s@%s", m.Immigrant.Abbr(), formatWard(m.WardID))
+	case moveFromAction:
+		return fmt.Sprintf("MV %s@%s ->", m.Immigrant.Abbr(), formatWard(m.WardID))
+	case moveToAction:
+		return fmt.Sprintf("MV -> %s@%s", m.Immigrant.Abbr(), formatWard(m.WardID))
+	case lockupAction:
+		return "LOCK " + formatWard(m.WardID)
+	case takeChipAction:
+		return "TAKE " + m.Chip.Abbr()
+	case deputyTakeChipAction:
+		return "DTAKE " + m.Chip.Abbr()
+	case slanderAction:
+		return fmt.Sprintf("SL %s %s -> %s", formatWard(m.WardID), m.Chip.Abbr(), formatPlayer(m.OtherPlayerID))
+	case bidAction:
+		ss := []string{"BID", formatWard(m.WardID)}
+		for _, n := range nationalityValues() {
+			if count := m.Bids[n]; count > 0 {
+				ss = append(ss, fmt.Sprintf("%s:%d", n.Abbr(), count))
+			}
+		}
+		return strings.Join(ss, " ")
+	case undoAction:
+		return "UNDO"
+	case redoAction:
+		return "REDO"
+	case resetAction:
+		return "RESET"
+	case cancelFinishAction:
+		return "CANCEL"
+	case finishAction:
+		return "FINISH"
+	default:
+		if adminActions[m.Action] == nil {
+			return fmt.Sprintf("?%s", m.Action)
+		}
+
+		ss := []string{"ADMIN", m.Action}
+		keys := make([]string, 0, len(m.Params))
+		for k := range m.Params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			for _, v := range m.Params[k] {
+				ss = append(ss, url.QueryEscape(k)+"="+url.QueryEscape(v))
+			}
+		}
+		return strings.Join(ss, " ")
+	}
+}
+
+// Values provides the form values posted to update in order to perform the move.
+func (m *Move) Values() url.Values {
+	vs := make(url.Values)
+	if m.Action != finishAction {
+		vs.Set("action", m.Action)
+	}
+
+	switch m.Action {
+	case selectAreaAction:
+		if m.Office != noOffice {
+			vs.Set("area", m.Office.IDString())
+		} else {
+			vs.Set("area", wardArea(m.WardID))
+		}
+	case assignOfficeAction:
+		vs.Set("area", m.Office.IDString())
+		vs.Set("pid", strconv.Itoa(m.OtherPlayerID))
+	case placePiecesAction:
+		vs.Set("area", wardArea(m.WardID))
+		vs.Set("bosses", strconv.Itoa(m.Bosses))
+		vs.Set("immigrant", nationalityValue(m.Immigrant))
+	case removeAction, moveFromAction, moveToAction:
+		vs.Set("area", wardArea(m.WardID))
+		vs.Set("immigrant", nationalityValue(m.Immigrant))
+	case lockupAction:
+		vs.Set("area", wardArea(m.WardID))
+	case takeChipAction, deputyTakeChipAction:
+		vs.Set("chip", nationalityValue(m.Chip))
+	case slanderAction:
+		vs.Set("area", wardArea(m.WardID))
+		vs.Set("slander-nationality", strconv.Itoa(m.Chip.Int()))
+		vs.Set("slandered-player", strconv.Itoa(m.OtherPlayerID))
+	case bidAction:
+		for _, n := range nationalityValues() {
+			vs.Set(fmt.Sprintf("%s-0", n.LString()), strconv.Itoa(m.Bids[n]))
+		}
+	default:
+		for k, v := range m.Params {
+			vs[k] = append([]string(nil), v...)
+		}
+	}
+	return vs
+}
+
+// moveFromValues provides the move performed by posting the form values to update or finish.
+// moveFromValues is the inverse of Values, except that the values of a finish may name the confirm-finish action.
+func moveFromValues(vs url.Values) (*Move, error) {
+	action := vs.Get("action")
+	if action == "" || action == "confirm-finish" {
+		action = finishAction
+	}
+	m := newMove(action)
+
+	var err error
+	switch action {
+	case selectAreaAction:
+		if o, ok := toOffice[vs.Get("area")]; ok {
+			m.Office = o
+		} else {
+			m.WardID, err = areaWard(vs)
+		}
+	case assignOfficeAction:
+		o, ok := toOffice[vs.Get("area")]
+		if !ok {
+			return nil, invalidMove("Invalid office %q.", vs.Get("area"))
+		}
+		m.Office = o
+		m.OtherPlayerID, err = valuesPlayer(vs, "pid")
+	case placePiecesAction:
+		m.WardID, err = areaWard(vs)
+		if err == nil {
+			m.Immigrant, err = valuesNationality(vs, "immigrant")
+		}
+		if err == nil && vs.Get("bosses") != "" {
+			m.Bosses, err = strconv.Atoi(vs.Get("bosses"))
+		}
+	case removeAction, moveFromAction, moveToAction:
+		m.WardID, err = areaWard(vs)
+		if err == nil {
+			m.Immigrant, err = valuesNationality(vs, "immigrant")
+		}
+	case lockupAction:
+		m.WardID, err = areaWard(vs)
+	case takeChipAction, deputyTakeChipAction:
+		m.Chip, err = valuesNationality(vs, "chip")
+	case slanderAction:
+		m.WardID, err = areaWard(vs)
+		if err == nil {
+			var n int
+			n, err = strconv.Atoi(vs.Get("slander-nationality"))
+			m.Chip = nationality(n)
+		}
+		if err == nil {
+			m.OtherPlayerID, err = valuesPlayer(vs, "slandered-player")
+		}
+	case bidAction:
+		// A bid is made in the current ward, which the values omit.
+		m.Bids = make(Chips)
+		for _, n := range nationalityValues() {
+			if v := vs.Get(fmt.Sprintf("%s-0", n.LString())); v != "" {
+				m.Bids[n], err = strconv.Atoi(v)
+				if err != nil {
+					break
+				}
+			}
+		}
+	case undoAction, redoAction, resetAction, cancelFinishAction, finishAction:
+	default:
+		keys := adminActions[action]
+		if keys == nil {
+			return nil, newValidationError(ErrInvalidAction, "%v is not a valid action.", action)
+		}
+		m.Params = make(url.Values)
+		for _, k := range keys {
+			if v, ok := vs[k]; ok {
+				m.Params[k] = append([]string(nil), v...)
+			}
+		}
+	}
+	if err != nil {
+		return nil, invalidMove("Invalid values for %s: %v", action, err)
+	}
+	return m, nil
+}
+
+func areaWard(vs url.Values) (wardID, error) {
+	w, ok := toWardID[vs.Get("area")]
+	if !ok {
+		return noWardID, fmt.Errorf("invalid ward %q", vs.Get("area"))
+	}
+	return w, nil
+}
+
+func valuesPlayer(vs url.Values, key string) (int, error) {
+	pid, err := strconv.Atoi(vs.Get(key))
+	if err != nil {
+		return noPlayerID, fmt.Errorf("invalid player %q", vs.Get(key))
+	}
+	return pid, nil
+}
+
+func valuesNationality(vs url.Values, key string) (nationality, error) {
+	v := vs.Get(key)
+	if v == "" {
+		return noNationality, nil
+	}
+
+	n, ok := toNationality[v]
+	if !ok {
+		return noNationality, fmt.Errorf("invalid nationality %q", v)
+	}
+	return n, nil
+}
+
+func wardArea(w wardID) string {
+	return fmt.Sprintf("ward-%d", w)
+}
+
+func nationalityValue(n nationality) string {
+	if n == noNationality {
+		return "none"
+	}
+	return n.LString()
+}
+
+func nationalityValues() []nationality {
+	return []nationality{irish, english, german, italian}
+}
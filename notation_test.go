@@ -0,0 +1,114 @@
+package tammany
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestMoveNotationRoundTrip(t *testing.T) {
+	notations := []string{
+		"Y1 P1: SEL W6",
+		"P2: SEL DM",
+		"ASSIGN COP P3",
+		"Y3 P2: B2+Irish@W6",
+		"B1@W14",
+		"Ger@W4",
+		"RM Ita@W7",
+		"MV Eng@W5 ->",
+		"MV -> Eng@W8",
+		"LOCK W10",
+		"TAKE Irish",
+		"DTAKE Ita",
+		"SL W5 Ger -> P3",
+		"BID W14 Irish:2 Ger:1",
+		"UNDO",
+		"REDO",
+		"RESET",
+		"CANCEL",
+		"Y2 P4: FINISH",
+		"ADMIN admin-edits edits=%5B%5D reason=lost+points",
+	}
+
+	for _, s := range notations {
+		m, err := ParseMove(s)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+			continue
+		}
+		if got := m.String(); got != s {
+			t.Errorf("got %q, want %q", got, s)
+		}
+
+		// The values of a move omit its context and, for a bid, the ward of the election.
+		m2, err := moveFromValues(m.Values())
+		if err != nil {
+			t.Errorf("%q: values %v: %v", s, m.Values(), err)
+			continue
+		}
+		m2.Year, m2.PlayerID = m.Year, m.PlayerID
+		if m.Action == bidAction {
+			m2.WardID = m.WardID
+		}
+		if got := m2.String(); got != s {
+			t.Errorf("got %q from values %v, want %q", got, m.Values(), s)
+		}
+	}
+}
+
+func TestInvalidMoveNotation(t *testing.T) {
+	for _, s := range []string{"", "Y0: FINISH", "SEL", "B2+Irish@W99", "RM Dutch@W6", "FINISH now", "BID W14 Irish:x", "ADMIN drop"} {
+		_, err := ParseMove(s)
+		if !errors.Is(err, ErrInvalidMove) {
+			t.Errorf("%q: got error %v, want an invalid move", s, err)
+		}
+	}
+}
+
+// secretAdminValues provides values of the game-state form that must never be noted.
+var secretAdminValues = url.Values{
+	"password":     {"s3cret"},
+	"user-ids":     {"1", "2", "3"},
+	"user-sids":    {"1", "2", "3"},
+	"user-names":   {"player1", "player2", "player3"},
+	"user-emails":  {"player1@example.com", "player2@example.com", "player3@example.com"},
+	"creator-id":   {"1"},
+	"creator-sid":  {"1"},
+	"creator-name": {"player1"},
+}
+
+func TestRecordedAdminActionOmitsSecrets(t *testing.T) {
+	g := newTestGame(t, 3)
+
+	vs := url.Values{"action": {"game-state"}, "reason": {"fix title"}, "title": {"renamed"}, "num-players": {"3"}}
+	for k, v := range secretAdminValues {
+		vs[k] = v
+	}
+	c := formContext(t, vs)
+	err := c.Request.ParseForm()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.recordUpdate(c, testAdmin())
+	got := g.Actions[len(g.Actions)-1]
+	if !strings.Contains(got, "title=renamed") || !strings.Contains(got, "reason=fix+title") {
+		t.Errorf("got %q, want the title and reason noted", got)
+	}
+	for k := range secretAdminValues {
+		if strings.Contains(got, k+"=") {
+			t.Errorf("got %q, which notes %s", got, k)
+		}
+	}
+}
+
+func TestParseAdminDropsSecrets(t *testing.T) {
+	m, err := ParseMove("ADMIN game-state password=s3cret reason=r title=t user-emails=a%40x.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.String(), "ADMIN game-state reason=r title=t"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
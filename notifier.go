@@ -0,0 +1,292 @@
+package tammany
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/SlothNinja/send"
+	"github.com/gin-gonic/gin"
+	"github.com/mailjet/mailjet-apiv3-go"
+)
+
+// Kinds of notifications.
+const (
+	turnNotice     = "turn"
+	electionNotice = "election"
+	endGameNotice  = "end"
+	rewindNotice   = "rewind"
+	digestNotice   = "digest"
+)
+
+// Channels delivering notifications to a user.
+const (
+	emailChannel   = "email"
+	webhookChannel = "webhook"
+	discordChannel = "discord"
+	slackChannel   = "slack"
+)
+
+// maxChatLength limits the content of chat messages, as Discord rejects messages exceeding 2000 characters.
+const maxChatLength = 2000
+
+// notifyHTTPClient posts notifications to webhooks.
+// Webhook URLs are provided by users, so the client dials only public addresses, lest users have the server post to
+// itself, its private network, or the metadata service of its host.  The check is made as each connection is dialed,
+// so hosts resolving, or redirecting, to a non-public address are refused.
+var notifyHTTPClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: dialPublicOnly,
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+	},
+}
+
+// nonPublicNets provides the networks of private, shared, loopback, link-local and unspecified addresses.
+// Link-local addresses include the metadata service 169.254.169.254.
+var nonPublicNets = func() []*net.IPNet {
+	var ns []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12", "192.168.0.0/16",
+		"::/128", "::1/128", "fc00::/7", "fe80::/10",
+	} {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		ns = append(ns, n)
+	}
+	return ns
+}()
+
+// isPublicIP reports whether ip is a public unicast address.
+func isPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if !ip.IsGlobalUnicast() {
+		return false
+	}
+	for _, n := range nonPublicNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialPublicOnly refuses connections to addresses other than public addresses.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("webhook address %s is not public", host)
+	}
+	return nil
+}
+
+// Notification provides a message to a player, independent of the channel delivering it.
+type Notification struct {
+	Kind    string `json:"kind"`
+	GameID  int64  `json:"gameId"`
+	Title   string `json:"title"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	Link    string `json:"link"`
+
+	// HTML provides the body of an email.
+	HTML string `json:"-"`
+
+	// Board provides an SVG rendering of the board, if any, which emails attach as a file.
+	Board string `json:"board,omitempty"`
+}
+
+func (g *Game) newNotification(c *gin.Context, kind string) *Notification {
+	return &Notification{
+		Kind:   kind,
+		GameID: g.ID(),
+		Title:  g.Title,
+		Link:   imageBase(c) + showPath(g.Type.Prefix(), fmt.Sprint(g.ID())),
+	}
+}
+
+// Notifier delivers notifications through a channel.
+type Notifier interface {
+	Notify(*gin.Context, *Notification) error
+}
+
+// emailNotifier delivers notifications by mailjet.
+type emailNotifier struct {
+	email, name string
+}
+
+func (n *emailNotifier) Notify(c *gin.Context, note *Notification) error {
+	_, err := send.Messages(c, n.message(note))
+	return err
+}
+
+// message provides the email of the notification, which attaches the board as a file,
+// since many webmail clients show no inlined SVG images.
+func (n *emailNotifier) message(note *Notification) mailjet.InfoMessagesV31 {
+	msg := mailjet.InfoMessagesV31{
+		From: &mailjet.RecipientV31{
+			Email: "webmaster@slothninja.com",
+			Name:  "Webmaster",
+		},
+		To: &mailjet.RecipientsV31{
+			mailjet.RecipientV31{
+				Email: n.email,
+				Name:  n.name,
+			},
+		},
+		Subject:  note.Subject,
+		TextPart: note.Text,
+		HTMLPart: note.HTML,
+	}
+
+	if note.Board != "" {
+		msg.Attachments = &mailjet.AttachmentsV31{
+			mailjet.AttachmentV31{
+				ContentType:   "image/svg+xml",
+				Base64Content: base64.StdEncoding.EncodeToString([]byte(note.Board)),
+				Filename:      fmt.Sprintf("tammany-%d.svg", note.GameID),
+			},
+		}
+	}
+	return msg
+}
+
+// webhookNotifier posts notifications as JSON to a URL.
+type webhookNotifier struct {
+	url string
+}
+
+func (n *webhookNotifier) Notify(c *gin.Context, note *Notification) error {
+	return postJSON(c, n.url, note)
+}
+
+// chatNotifier posts notifications to the incoming webhook of a chat service,
+// which displays the value of the field of the posted JSON object.
+// Discord displays the field "content", and Slack the field "text".
+type chatNotifier struct {
+	url, field string
+}
+
+func (n *chatNotifier) Notify(c *gin.Context, note *Notification) error {
+	content := fmt.Sprintf("%s\n%s\n\n%s", note.Subject, note.Link, note.Text)
+	if len(content) > maxChatLength {
+		content = content[:maxChatLength-3] + "..."
+	}
+	return postJSON(c, n.url, map[string]string{n.field: content})
+}
+
+func postJSON(c *gin.Context, u string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if c != nil && c.Request != nil {
+		req = req.WithContext(c.Request.Context())
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := notifyHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %s", resp.Status)
+	}
+	return nil
+}
+
+// validateChannel ensures the channel is known and, for channels other than email, that the webhook URL is absolute
+// and names neither a local host nor a non-public address.  Discord and Slack webhooks require https.
+func validateChannel(channel, webhookURL string) error {
+	switch channel {
+	case "", emailChannel:
+		return nil
+	case webhookChannel, discordChannel, slackChannel:
+		u, err := url.Parse(webhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+			return fmt.Errorf("%s notifications require an http or https webhook URL", channel)
+		}
+		if channel != webhookChannel && u.Scheme != "https" {
+			return fmt.Errorf("%s notifications require an https webhook URL", channel)
+		}
+
+		host := strings.ToLower(u.Hostname())
+		if ip := net.ParseIP(host); (ip != nil && !isPublicIP(ip)) || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return fmt.Errorf("%s notifications require a webhook URL of a public host", channel)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown notification channel %q", channel)
+	}
+}
+
+// notifierFor provides the notifier of the channel preferred by the user having the email and name.
+func notifierFor(email, name string, prefs *Prefs) Notifier {
+	switch prefs.Channel {
+	case webhookChannel:
+		return &webhookNotifier{url: prefs.WebhookURL}
+	case discordChannel:
+		return &chatNotifier{url: prefs.WebhookURL, field: "content"}
+	case slackChannel:
+		return &chatNotifier{url: prefs.WebhookURL, field: "text"}
+	default:
+		return &emailNotifier{email: email, name: name}
+	}
+}
+
+// notify delivers the notification built in the language preferred by the player through the channel preferred by the player.
+func (client *Client) notify(c *gin.Context, g *Game, p *Player, build func(lang string) (*Notification, error)) error {
+	return client.notifyUser(c, g.UserIDS[p.ID()], g.EmailFor(p), g.NameFor(p), build)
+}
+
+// notifyUser delivers the notification built in the language preferred by the user through the channel preferred by the user.
+// Users preferring a daily digest are not notified, unless they receive notifications other than by email.
+func (client *Client) notifyUser(c *gin.Context, uid int64, email, name string, build func(lang string) (*Notification, error)) error {
+	prefs, err := client.Prefs.GetPrefs(c, uid)
+	if err != nil {
+		client.Log.Warningf("unable to get preferences of user %d: %v", uid, err)
+		prefs = newPrefs(uid)
+	}
+
+	if prefs.Digest && (prefs.Channel == "" || prefs.Channel == emailChannel) {
+		return nil
+	}
+
+	return client.deliver(c, prefs, email, name, build)
+}
+
+// deliver delivers the notification built in the language preferred by the user having preferences prefs
+// through the channel preferred by the user.
+func (client *Client) deliver(c *gin.Context, prefs *Prefs, email, name string, build func(lang string) (*Notification, error)) error {
+	note, err := build(prefs.language())
+	if err != nil {
+		return err
+	}
+	return notifierFor(email, name, prefs).Notify(c, note)
+}
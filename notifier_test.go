@@ -0,0 +1,216 @@
+package tammany
+
+import (
+	"encoding/json"
+	"html"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testRewind logs a rewind of the game for notification.
+func testRewind(g *Game) *rewoundEntry {
+	e := &rewoundEntry{Entry: g.newEntry(), AdminName: "admin", Reason: "misclick", EntryIndex: 2, Removed: 3}
+	g.Log = append(g.Log, e)
+	return e
+}
+
+// testWebhook provides a server recording the bodies posted to it, responding with the status.
+// Notifications are posted to the server, although it listens on a loopback address, until the test ends.
+func testWebhook(t *testing.T, status int, bodies *[][]byte) *httptest.Server {
+	srv := newRecordingServer(t, status, bodies)
+	client := notifyHTTPClient
+	notifyHTTPClient = srv.Client()
+	t.Cleanup(func() { notifyHTTPClient = client })
+	return srv
+}
+
+func newRecordingServer(t *testing.T, status int, bodies *[][]byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s request of %q, want a JSON post", r.Method, r.Header.Get("Content-Type"))
+		}
+		bs, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		*bodies = append(*bodies, bs)
+		w.WriteHeader(status)
+	}))
+}
+
+func TestNotifyThroughWebhooks(t *testing.T) {
+	for _, channel := range []string{webhookChannel, discordChannel, slackChannel} {
+		t.Run(channel, func(t *testing.T) {
+			client, c := newTestClient(), testContext()
+			g := newTestGame(t, 3)
+			e := testRewind(g)
+
+			var bodies [][]byte
+			srv := testWebhook(t, http.StatusNoContent, &bodies)
+			defer srv.Close()
+
+			p := g.Players()[0]
+			prefs := newPrefs(g.UserIDS[p.ID()])
+			prefs.Channel, prefs.WebhookURL = channel, srv.URL
+			err := client.Prefs.PutPrefs(c, prefs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = client.notify(c, g, p, func(lang string) (*Notification, error) {
+				return g.rewindNotification(c, e, lang)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(bodies) != 1 {
+				t.Fatalf("got %d posts, want 1", len(bodies))
+			}
+
+			var content string
+			switch channel {
+			case webhookChannel:
+				var note Notification
+				err = json.Unmarshal(bodies[0], &note)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if note.Kind != rewindNotice || note.GameID != g.ID() {
+					t.Errorf("got %s notification of game %d, want rewind of game %d", note.Kind, note.GameID, g.ID())
+				}
+				content = note.Subject + "\n" + note.Text
+			default:
+				var msg map[string]string
+				err = json.Unmarshal(bodies[0], &msg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				field := map[string]string{discordChannel: "content", slackChannel: "text"}[channel]
+				if len(msg) != 1 {
+					t.Errorf("got fields %v, want only %q", msg, field)
+				}
+				content = msg[field]
+			}
+
+			if !strings.HasPrefix(content, "SlothNinja Games: Tammany Hall #1 Was Rewound") || !strings.Contains(content, e.Text(g)) {
+				t.Errorf("got %q, want the subject and the rewind entry", content)
+			}
+		})
+	}
+}
+
+func TestNotifyFailsOnErrorStatus(t *testing.T) {
+	var bodies [][]byte
+	srv := testWebhook(t, http.StatusInternalServerError, &bodies)
+	defer srv.Close()
+
+	n := notifierFor("", "", &Prefs{Channel: discordChannel, WebhookURL: srv.URL})
+	err := n.Notify(testContext(), &Notification{Subject: "subject"})
+	if err == nil || len(bodies) != 1 {
+		t.Errorf("got error %v after %d posts, want an error after 1 post", err, len(bodies))
+	}
+}
+
+func TestRewindNotificationInGerman(t *testing.T) {
+	g := newTestGame(t, 3)
+	e := testRewind(g)
+
+	note, err := g.rewindNotification(testContext(), e, "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Subject != "SlothNinja Games: Tammany Hall #1 wurde zurückgesetzt" {
+		t.Errorf("got subject %q", note.Subject)
+	}
+	want := "Admin admin hat das Spiel auf Eintrag 2 zurückgesetzt und 3 spätere Einträge entfernt. Grund: misclick"
+	if !strings.Contains(note.Text, want) || !strings.Contains(note.HTML, want) {
+		t.Errorf("got text %q, want the entry %q", note.Text, want)
+	}
+}
+
+func TestTurnEmailAttachesBoard(t *testing.T) {
+	g := newTestGame(t, 3)
+	p := g.CurrentPlayer()
+
+	note, err := g.turnNotificationFor(testContext(), p, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	board := html.EscapeString(g.TextBoard(p.User()))
+	if strings.Contains(note.HTML, "cid:") || !strings.Contains(note.HTML, board) {
+		t.Errorf("got HTML %q, want the text board instead of an inlined image", note.HTML)
+	}
+
+	msg := (&emailNotifier{email: "player@example.com"}).message(note)
+	if msg.InlinedAttachments != nil || msg.Attachments == nil || len(*msg.Attachments) != 1 {
+		t.Fatalf("got attachments %v and inlined attachments %v, want the board attached", msg.Attachments, msg.InlinedAttachments)
+	}
+	if a := (*msg.Attachments)[0]; a.ContentType != "image/svg+xml" || a.Filename != "tammany-1.svg" {
+		t.Errorf("got attachment %s of type %s, want tammany-1.svg of type image/svg+xml", a.Filename, a.ContentType)
+	}
+}
+
+func TestNotifyRefusesNonPublicAddresses(t *testing.T) {
+	var bodies [][]byte
+	srv := newRecordingServer(t, http.StatusNoContent, &bodies)
+	defer srv.Close()
+
+	n := notifierFor("", "", &Prefs{Channel: webhookChannel, WebhookURL: srv.URL})
+	err := n.Notify(testContext(), &Notification{Subject: "subject"})
+	if err == nil || len(bodies) != 0 {
+		t.Errorf("got error %v after %d posts, want the loopback address refused", err, len(bodies))
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for ip, public := range map[string]bool{
+		"8.8.8.8":            true,
+		"2606:4700::1111":    true,
+		"127.0.0.1":          false,
+		"10.1.2.3":           false,
+		"172.16.0.1":         false,
+		"192.168.1.1":        false,
+		"100.64.0.1":         false,
+		"169.254.169.254":    false,
+		"0.0.0.0":            false,
+		"::1":                false,
+		"fd00::1":            false,
+		"fe80::1":            false,
+		"::ffff:127.0.0.1":   false,
+		"::ffff:169.254.0.1": false,
+	} {
+		if got := isPublicIP(net.ParseIP(ip)); got != public {
+			t.Errorf("%s: got public %v, want %v", ip, got, public)
+		}
+	}
+}
+
+func TestValidateChannel(t *testing.T) {
+	tests := []struct {
+		channel, url string
+		valid        bool
+	}{
+		{emailChannel, "", true},
+		{webhookChannel, "http://example.com/hook", true},
+		{webhookChannel, "https://example.com/hook", true},
+		{discordChannel, "https://discord.com/api/webhooks/1/x", true},
+		{discordChannel, "http://discord.com/api/webhooks/1/x", false},
+		{slackChannel, "http://hooks.slack.com/services/x", false},
+		{webhookChannel, "ftp://example.com/hook", false},
+		{webhookChannel, "http://localhost:8080/hook", false},
+		{webhookChannel, "http://127.0.0.1/hook", false},
+		{webhookChannel, "http://169.254.169.254/latest/meta-data", false},
+		{webhookChannel, "http://[::1]/hook", false},
+		{webhookChannel, "http://10.0.0.1/hook", false},
+		{"pigeon", "https://example.com/hook", false},
+	}
+	for _, test := range tests {
+		if err := validateChannel(test.channel, test.url); (err == nil) != test.valid {
+			t.Errorf("%s %q: got error %v, want valid %v", test.channel, test.url, err, test.valid)
+		}
+	}
+}
@@ -8,14 +8,15 @@ import (
 
 	"github.com/SlothNinja/game"
 	"github.com/SlothNinja/restful"
-	"github.com/SlothNinja/sn"
 	"github.com/SlothNinja/user"
 	"github.com/gin-gonic/gin"
 )
 
 func init() {
 	gob.RegisterName("*game.placedLockUpMarkerEntry", new(placedLockUpMarkerEntry))
+	registerEntry("placedLockUpMarker", new(placedLockUpMarkerEntry))
 	gob.RegisterName("*game.movedImmigrantEntry", new(movedImmigrantEntry))
+	registerEntry("movedImmigrant", new(movedImmigrantEntry))
 }
 
 type office int
@@ -244,28 +245,38 @@ func (g *Game) newPlacedLockUpMarkerEntryFor(p *Player) *placedLockUpMarkerEntry
 }
 
 func (e *placedLockUpMarkerEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	return restful.HTML("%s locked-up ward %d.", g.NameByPID(e.PlayerID), e.WardID)
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *placedLockUpMarkerEntry) Text(g *Game) string {
+	return g.T("%s locked-up ward %d.", g.NameByPID(e.PlayerID), e.WardID)
+}
+
+func (e *placedLockUpMarkerEntry) Event() *Event {
+	ev := e.event(e)
+	ev.WardID = int(e.WardID)
+	return ev
 }
 
 func (g *Game) validatePlaceLockupMarker(c *gin.Context, cu *user.User) (*Ward, error) {
 	w, cp, prez := g.getWard(c), g.CurrentPlayer(), g.councilPresident()
 	switch {
 	case !g.IsCurrentPlayer(cu):
-		return nil, sn.NewVError("Only the current player can lockup a ward.")
+		return nil, g.verror(ErrNotCurrentPlayer, "Only the current player can lockup a ward.")
 	case w == nil:
-		return nil, sn.NewVError("You must first select a ward.")
+		return nil, g.verror(ErrNoWardSelected, "You must first select a ward.")
 	case w.LockedUp:
-		return nil, sn.NewVError("You can't place lockup an already locked ward.")
+		return nil, g.verror(ErrWardLocked, "You can't place lockup an already locked ward.")
 	case cp.UsedOffice:
-		return nil, sn.NewVError("You have already lockedup a ward this year.")
+		return nil, g.verror(ErrLimitReached, "You have already lockedup a ward this year.")
 	case cp.hasPlacedOnePiece():
-		return nil, sn.NewVError("You are in the process of placing pieces (immigrants and/or bosses).  You must use office before or after placing pieces, but not during.")
+		return nil, g.verror(ErrPlacingPieces, "You are in the process of placing pieces (immigrants and/or bosses).  You must use office before or after placing pieces, but not during.")
 	case !g.inActionPhase():
-		return nil, sn.NewVError("Wrong phase for performing this action.")
+		return nil, g.verror(ErrWrongPhase, "Wrong phase for performing this action.")
 	case cp.LockedUp >= 2:
-		return nil, sn.NewVError("You have already lockedup two wards this term.")
+		return nil, g.verror(ErrLimitReached, "You have already lockedup two wards this term.")
 	case cp.NotEqual(prez):
-		return nil, sn.NewVError("You are the %s.  Only the Council President may lockup a ward.", cp.Office)
+		return nil, g.verror(ErrWrongOffice, "You are the %s.  Only the Council President may lockup a ward.", cp.Office)
 	default:
 		return w, nil
 	}
@@ -298,19 +309,19 @@ func (g *Game) validateMoveFrom(c *gin.Context, cu *user.User) (w *Ward, n natio
 
 	switch n, w, cp, chairman = getNationality(c), g.getWard(c), g.CurrentPlayer(), g.precinctChairman(); {
 	case !g.IsCurrentPlayer(cu):
-		err = sn.NewVError("Only the current player can move an immigrant between wards.")
+		err = g.verror(ErrNotCurrentPlayer, "Only the current player can move an immigrant between wards.")
 	case w == nil:
-		err = sn.NewVError("You must first select a ward.")
+		err = g.verror(ErrNoWardSelected, "You must first select a ward.")
 	case w.LockedUp:
-		err = sn.NewVError("You can't move an immigrant from a locked ward.")
+		err = g.verror(ErrWardLocked, "You can't move an immigrant from a locked ward.")
 	case cp.placedPieces() == 1:
-		err = sn.NewVError("You must move an immigrant before or after the placing pieces action, not during.")
+		err = g.verror(ErrPlacingPieces, "You must move an immigrant before or after the placing pieces action, not during.")
 	case !g.inActionPhase():
-		err = sn.NewVError("You can't move an immigrant during the %s phase.", g.Phase)
+		err = g.verror(ErrWrongPhase, "You can't move an immigrant during the %s phase.", g.Phase)
 	case w.hasOneImmigrant():
-		err = sn.NewVError("You can't move the last immigrant from the ward.")
+		err = g.verror(ErrLastImmigrant, "You can't move the last immigrant from the ward.")
 	case cp.NotEqual(chairman):
-		err = sn.NewVError("You are the %s.  Only the Precinct Chairman can move an immigrant between wards.", cp.Office)
+		err = g.verror(ErrWrongOffice, "You are the %s.  Only the Precinct Chairman can move an immigrant between wards.", cp.Office)
 	}
 	return
 }
@@ -365,31 +376,42 @@ func (g *Game) newMovedImmigrantEntryFor(p *Player, fromID, toID wardID, n natio
 }
 
 func (e *movedImmigrantEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	p := g.PlayerByID(e.PlayerID)
-	return restful.HTML("%s moved a %s immigrant from ward %d to ward %d.", g.NameFor(p), e.Immigrant, e.FromWardID, e.ToWardID)
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *movedImmigrantEntry) Text(g *Game) string {
+	return g.T("%s moved a %s immigrant from ward %d to ward %d.", g.NameByPID(e.PlayerID), e.Immigrant, e.FromWardID, e.ToWardID)
+}
+
+func (e *movedImmigrantEntry) Event() *Event {
+	ev := e.event(e)
+	ev.WardID = int(e.FromWardID)
+	ev.ToWardID = int(e.ToWardID)
+	ev.Nationality = e.Immigrant.eventString()
+	return ev
 }
 
 func (g *Game) validateMoveTo(c *gin.Context, cu *user.User) (*Ward, nationality, error) {
 	n, w, cp, chairman := getNationality(c), g.getWard(c), g.CurrentPlayer(), g.precinctChairman()
 	switch {
 	case !g.IsCurrentPlayer(cu):
-		return nil, noNationality, sn.NewVError("Only the current player can place a boss.")
+		return nil, noNationality, g.verror(ErrNotCurrentPlayer, "Only the current player can place a boss.")
 	case w == nil:
-		return nil, noNationality, sn.NewVError("You must first select a ward.")
+		return nil, noNationality, g.verror(ErrNoWardSelected, "You must first select a ward.")
 	case w.LockedUp:
-		return nil, noNationality, sn.NewVError("You can't move an immigrant to a locked ward.")
+		return nil, noNationality, g.verror(ErrWardLocked, "You can't move an immigrant to a locked ward.")
 	case cp.UsedOffice:
-		return nil, noNationality, sn.NewVError("You have already used your office power.")
+		return nil, noNationality, g.verror(ErrLimitReached, "You have already used your office power.")
 	case cp.UsedOffice:
-		return nil, noNationality, sn.NewVError("You have already used your office power.")
+		return nil, noNationality, g.verror(ErrLimitReached, "You have already used your office power.")
 	case g.Bag[n] <= 0:
-		return nil, noNationality, sn.NewVError("The Immigrant Bag does not have a %s cube to place.", n)
+		return nil, noNationality, g.verror(ErrNoImmigrant, "The Immigrant Bag does not have a %s cube to place.", n)
 	case g.ImmigrantInTransit != n:
-		return nil, noNationality, sn.NewVError("Expected placement of %s immigrant, but received placement of %s immigrant.", g.ImmigrantInTransit, n)
+		return nil, noNationality, g.verror(ErrInvalidValue, "Expected placement of %s immigrant, but received placement of %s immigrant.", g.ImmigrantInTransit, n)
 	case chairman != nil && !cp.Equal(chairman):
-		return nil, noNationality, sn.NewVError("You are the %s.  Only the Precinct Chairman can move an immigrant between wards.", cp.Office)
+		return nil, noNationality, g.verror(ErrWrongOffice, "You are the %s.  Only the Precinct Chairman can move an immigrant between wards.", cp.Office)
 	case !w.adjacent(g.moveFromWard()):
-		return nil, noNationality, sn.NewVError("Ward %d is not adjacent to ward %d.", w.ID, g.MoveFromWardID)
+		return nil, noNationality, g.verror(ErrNotAdjacent, "Ward %d is not adjacent to ward %d.", w.ID, g.MoveFromWardID)
 	default:
 		return w, n, nil
 	}
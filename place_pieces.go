@@ -9,19 +9,25 @@ import (
 	"github.com/SlothNinja/game"
 	"github.com/SlothNinja/log"
 	"github.com/SlothNinja/restful"
-	"github.com/SlothNinja/sn"
 	"github.com/SlothNinja/user"
 	"github.com/gin-gonic/gin"
 )
 
 func init() {
 	gob.Register(new(placedPiecesEntry))
+	registerEntry("placedPieces", new(placedPiecesEntry))
 	gob.RegisterName("*game.placedBossesEntry", new(placedBossesEntry))
+	registerEntry("placedBosses", new(placedBossesEntry))
 	gob.RegisterName("*game.placedBossEntry", new(placedBossEntry))
+	registerEntry("placedBoss", new(placedBossEntry))
 	gob.RegisterName("*game.placedImmigrantEntry", new(placedImmigrantEntry))
+	registerEntry("placedImmigrant", new(placedImmigrantEntry))
 	gob.RegisterName("*game.removedImmigrantEntry", new(removedImmigrantEntry))
+	registerEntry("removedImmigrant", new(removedImmigrantEntry))
 	gob.RegisterName("*game.placedBossAndImmigrantEntry", new(placedBossAndImmigrantEntry))
+	registerEntry("placedBossAndImmigrant", new(placedBossAndImmigrantEntry))
 	gob.RegisterName("*game.takeChipEntry", new(takeChipEntry))
+	registerEntry("takeChip", new(takeChipEntry))
 }
 
 func (g *Game) placePieces(c *gin.Context, cu *user.User) (tmpl string, act game.ActionType, err error) {
@@ -92,38 +98,38 @@ func (g *Game) validatePlacePieces(c *gin.Context, cu *user.User) (b int, n nati
 	switch {
 	// General Checks
 	case w == nil:
-		err = sn.NewVError("You must first select a ward.")
+		err = g.verror(ErrNoWardSelected, "You must first select a ward.")
 	case w.LockedUp:
-		err = sn.NewVError("You can't place pieces into a locked ward.")
+		err = g.verror(ErrWardLocked, "You can't place pieces into a locked ward.")
 	case cp.PerformedAction:
-		err = sn.NewVError("You have already performed an action.")
+		err = g.verror(ErrAlreadyActed, "You have already performed an action.")
 	// Phase Related Checks
 	case g.Phase == actions:
 		switch {
 		case b < 0, b > 2:
-			err = sn.NewVError("You cannot place %d bosses.", b)
+			err = g.verror(ErrInvalidValue, "You cannot place %d bosses.", b)
 		case count < 1, count > 2:
-			err = sn.NewVError("You cannot place %d pieces.", count)
+			err = g.verror(ErrInvalidValue, "You cannot place %d pieces.", count)
 		case count+cp.placedPieces() > 2:
-			err = sn.NewVError("You already placed %d pieces.  You cannot place %d more pieces.", cp.placedPieces(), count)
+			err = g.verror(ErrLimitReached, "You already placed %d pieces.  You cannot place %d more pieces.", cp.placedPieces(), count)
 		case n != noNationality && g.CastleGarden[n] < 1:
-			err = sn.NewVError("There is not a %s immigrant in the Castle Garden", n)
+			err = g.verror(ErrNoImmigrant, "There is not a %s immigrant in the Castle Garden", n)
 		case n != noNationality && cp.PlacedImmigrants >= 1:
-			err = sn.NewVError("You already placed %d immigrants.  You cannot place another immigrant.", cp.PlacedImmigrants)
+			err = g.verror(ErrLimitReached, "You already placed %d immigrants.  You cannot place another immigrant.", cp.PlacedImmigrants)
 		default:
 			cb = n
 		}
 	case g.Phase == placeImmigrant:
 		switch {
 		case b != 0:
-			err = sn.NewVError("You cannot place a boss.")
+			err = g.verror(ErrInvalidValue, "You cannot place a boss.")
 		case count != 1:
-			err = sn.NewVError("You must place 1 immigrant.")
+			err = g.verror(ErrInvalidValue, "You must place 1 immigrant.")
 		case n == noNationality:
-			err = sn.NewVError("You selected an invalid nationality.")
+			err = g.verror(ErrInvalidValue, "You selected an invalid nationality.")
 		}
 	default:
-		err = sn.NewVError("Wrong phase for performing this action.")
+		err = g.verror(ErrWrongPhase, "Wrong phase for performing this action.")
 	}
 	log.Errorf("err: %v", err)
 	return
@@ -151,18 +157,37 @@ func (g *Game) newPlacedPiecesEntryFor(p *Player, b int, n, c nationality, w *Wa
 }
 
 func (e *placedPiecesEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *placedPiecesEntry) Text(g *Game) string {
 	var ss []string
 	if e.Bosses > 0 {
-		ss = append(ss, fmt.Sprintf("placed %d %s in ward %d",
-			e.Bosses, restful.Pluralize("boss", e.Bosses), e.WardID))
+		if e.Bosses == 1 {
+			ss = append(ss, g.T("placed 1 boss in ward %d", e.WardID))
+		} else {
+			ss = append(ss, g.T("placed %d bosses in ward %d", e.Bosses, e.WardID))
+		}
 	}
 	if e.Immigrant != noNationality {
-		ss = append(ss, fmt.Sprintf("placed 1 %s immigrant in ward %d", e.Immigrant, e.WardID))
+		ss = append(ss, g.T("placed 1 %s immigrant in ward %d", e.Immigrant, e.WardID))
+	}
+	if e.Chip != noNationality {
+		ss = append(ss, g.T("received 1 %s favor", e.Chip))
 	}
+	return fmt.Sprintf("%s %s.", g.NameByPID(e.PlayerID), g.toSentence(ss))
+}
+
+func (e *placedPiecesEntry) Event() *Event {
+	ev := e.event(e)
+	ev.WardID = int(e.WardID)
+	ev.Bosses = e.Bosses
+	ev.Nationality = e.Immigrant.eventString()
+	ev.Chip = e.Chip.eventString()
 	if e.Chip != noNationality {
-		ss = append(ss, fmt.Sprintf("received 1 %s favor", e.Chip))
+		ev.Chips = 1
 	}
-	return restful.HTML("%s %s.", g.NameByPID(e.PlayerID), restful.ToSentence(ss))
+	return ev
 }
 
 func getBosses(c *gin.Context) (int, error) {
@@ -194,7 +219,18 @@ func (g *Game) newPlacedBossesEntryFor(p *Player, ward *Ward) *placedBossesEntry
 }
 
 func (e *placedBossesEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	return restful.HTML("%s placed two bosses in ward %d.", g.NameByPID(e.PlayerID), e.WardID)
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *placedBossesEntry) Text(g *Game) string {
+	return g.T("%s placed two bosses in ward %d.", g.NameByPID(e.PlayerID), e.WardID)
+}
+
+func (e *placedBossesEntry) Event() *Event {
+	ev := e.event(e)
+	ev.WardID = int(e.WardID)
+	ev.Bosses = 2
+	return ev
 }
 
 func (p *Player) hasPlacedOnePiece() bool {
@@ -216,7 +252,18 @@ func (g *Game) newPlacedBossEntryFor(p *Player, w *Ward) *placedBossEntry {
 }
 
 func (e *placedBossEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	return restful.HTML("%s placed a boss in ward %d.", g.NameByPID(e.PlayerID), e.WardID)
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *placedBossEntry) Text(g *Game) string {
+	return g.T("%s placed a boss in ward %d.", g.NameByPID(e.PlayerID), e.WardID)
+}
+
+func (e *placedBossEntry) Event() *Event {
+	ev := e.event(e)
+	ev.WardID = int(e.WardID)
+	ev.Bosses = 1
+	return ev
 }
 
 func (g *Game) removeImmigrant(c *gin.Context, cu *user.User) (tmpl string, act game.ActionType, err error) {
@@ -264,7 +311,18 @@ func (g *Game) newRemovedImmigrantEntryFor(p *Player, w *Ward, n nationality) *r
 }
 
 func (e *removedImmigrantEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	return restful.HTML("%s removed a %s immigrant from ward %d.", g.NameByPID(e.PlayerID), e.Immigrant, e.WardID)
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *removedImmigrantEntry) Text(g *Game) string {
+	return g.T("%s removed a %s immigrant from ward %d.", g.NameByPID(e.PlayerID), e.Immigrant, e.WardID)
+}
+
+func (e *removedImmigrantEntry) Event() *Event {
+	ev := e.event(e)
+	ev.WardID = int(e.WardID)
+	ev.Nationality = e.Immigrant.eventString()
+	return ev
 }
 
 func (g *Game) validateRemoveImmigrant(c *gin.Context, cu *user.User) (*Ward, nationality, error) {
@@ -275,19 +333,19 @@ func (g *Game) validateRemoveImmigrant(c *gin.Context, cu *user.User) (*Ward, na
 
 	switch {
 	case !g.IsCurrentPlayer(cu):
-		return nil, noNationality, sn.NewVError("Only the current player can remove an immigrant from a ward.")
+		return nil, noNationality, g.verror(ErrNotCurrentPlayer, "Only the current player can remove an immigrant from a ward.")
 	case w == nil:
-		return nil, noNationality, sn.NewVError("You must first select a ward.")
+		return nil, noNationality, g.verror(ErrNoWardSelected, "You must first select a ward.")
 	case w.LockedUp:
-		return nil, noNationality, sn.NewVError("You can't remove an immigrant from a locked ward.")
+		return nil, noNationality, g.verror(ErrWardLocked, "You can't remove an immigrant from a locked ward.")
 	case cp.placedPieces() == 1:
-		return nil, noNationality, sn.NewVError("You must remove an immigrant before or after the placing pieces action, not during.")
+		return nil, noNationality, g.verror(ErrPlacingPieces, "You must remove an immigrant before or after the placing pieces action, not during.")
 	case !g.inActionPhase():
-		return nil, noNationality, sn.NewVError("You can't remove an immigrant during the %s phase.", g.Phase)
+		return nil, noNationality, g.verror(ErrWrongPhase, "You can't remove an immigrant during the %s phase.", g.Phase)
 	case w.hasOneImmigrant():
-		return nil, noNationality, sn.NewVError("You can't remove the last immigrant from the ward.")
+		return nil, noNationality, g.verror(ErrLastImmigrant, "You can't remove the last immigrant from the ward.")
 	case cp.NotEqual(chief):
-		return nil, noNationality, sn.NewVError("You are the %s.  Only the Chief of Police can remove an immigrant from the ward.", cp.Office)
+		return nil, noNationality, g.verror(ErrWrongOffice, "You are the %s.  Only the Chief of Police can remove an immigrant from the ward.", cp.Office)
 	}
 	return w, n, nil
 }
@@ -313,7 +371,18 @@ func (g *Game) newPlacedImmigrantEntryFor(p *Player, w *Ward, n nationality) *pl
 }
 
 func (e *placedImmigrantEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	return restful.HTML("%s placed a %s immigrant in ward %d.", g.NameByPID(e.PlayerID), e.Immigrant, e.WardID)
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *placedImmigrantEntry) Text(g *Game) string {
+	return g.T("%s placed a %s immigrant in ward %d.", g.NameByPID(e.PlayerID), e.Immigrant, e.WardID)
+}
+
+func (e *placedImmigrantEntry) Event() *Event {
+	ev := e.event(e)
+	ev.WardID = int(e.WardID)
+	ev.Nationality = e.Immigrant.eventString()
+	return ev
 }
 
 func (p *Player) hasPlacedImmigrants() bool {
@@ -337,7 +406,19 @@ func (g *Game) newPlacedBossAndImmigrantEntryFor(p *Player, w *Ward, n nationali
 }
 
 func (e *placedBossAndImmigrantEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	return restful.HTML("%s placed a boss and a %s immigrant in ward %d.", g.NameByPID(e.PlayerID), e.Immigrant, e.WardID)
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *placedBossAndImmigrantEntry) Text(g *Game) string {
+	return g.T("%s placed a boss and a %s immigrant in ward %d.", g.NameByPID(e.PlayerID), e.Immigrant, e.WardID)
+}
+
+func (e *placedBossAndImmigrantEntry) Event() *Event {
+	ev := e.event(e)
+	ev.WardID = int(e.WardID)
+	ev.Bosses = 1
+	ev.Nationality = e.Immigrant.eventString()
+	return ev
 }
 
 func (g *Game) deputyTakeChip(c *gin.Context, cu *user.User) (tmpl string, act game.ActionType, err error) {
@@ -365,7 +446,7 @@ func (g *Game) deputyTakeChip(c *gin.Context, cu *user.User) (tmpl string, act g
 func (g *Game) validateDeputyTakeChip(c *gin.Context, cu *user.User) (n nationality, err error) {
 	var ok bool
 	if n, ok = toNationality[c.PostForm("chip")]; !ok {
-		err = sn.NewVError("Invalid value received for chip nationatlity.")
+		err = g.verror(ErrInvalidValue, "Invalid value received for chip nationatlity.")
 		return
 	}
 
@@ -374,13 +455,13 @@ func (g *Game) validateDeputyTakeChip(c *gin.Context, cu *user.User) (n national
 
 	switch {
 	case !g.IsCurrentPlayer(cu):
-		err = sn.NewVError("Only the current player can take a chip.")
+		err = g.verror(ErrNotCurrentPlayer, "Only the current player can take a chip.")
 	case cp.UsedOffice:
-		err = sn.NewVError("You have already taken a favor chip.")
+		err = g.verror(ErrLimitReached, "You have already taken a favor chip.")
 	case !g.inActionPhase():
-		err = sn.NewVError("You can't take a favour chip in phase %q.", g.PhaseName())
+		err = g.verror(ErrWrongPhase, "You can't take a favour chip in phase %q.", g.Phase)
 	case cp.NotEqual(deputy):
-		err = sn.NewVError("You are the %s.  Only the Deputy Mayor may take a favor chip.", cp.Office)
+		err = g.verror(ErrWrongOffice, "You are the %s.  Only the Deputy Mayor may take a favor chip.", cp.Office)
 	}
 	return
 }
@@ -421,24 +502,35 @@ func (g *Game) newTakeChipEntryFor(p *Player, n nationality) *takeChipEntry {
 }
 
 func (e *takeChipEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	return restful.HTML("%s took a %s favor chip.", g.NameByPID(e.PlayerID), e.Chip)
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *takeChipEntry) Text(g *Game) string {
+	return g.T("%s took a %s favor chip.", g.NameByPID(e.PlayerID), e.Chip)
+}
+
+func (e *takeChipEntry) Event() *Event {
+	ev := e.event(e)
+	ev.Chip = e.Chip.eventString()
+	ev.Chips = 1
+	return ev
 }
 
 func (g *Game) validateTakeChip(c *gin.Context, cu *user.User) (nationality, error) {
 	n, ok := toNationality[c.PostForm("chip")]
 	if !ok {
-		return noNationality, sn.NewVError("Invalid value received for chip nationatlity.")
+		return noNationality, g.verror(ErrInvalidValue, "Invalid value received for chip nationatlity.")
 	}
 
 	cp := g.CurrentPlayer()
 
 	switch {
 	case !g.IsCurrentPlayer(cu):
-		return noNationality, sn.NewVError("Only the current player can take a chip.")
+		return noNationality, g.verror(ErrNotCurrentPlayer, "Only the current player can take a chip.")
 	case cp.PerformedAction:
-		return noNationality, sn.NewVError("You have already performed an action.")
+		return noNationality, g.verror(ErrAlreadyActed, "You have already performed an action.")
 	case g.Phase != takeFavorChip:
-		return noNationality, sn.NewVError("You can't take a favour chip in phase %q.", g.PhaseName())
+		return noNationality, g.verror(ErrWrongPhase, "You can't take a favour chip in phase %q.", g.Phase)
 	default:
 		return n, nil
 	}
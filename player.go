@@ -279,6 +279,17 @@ func (cs Chips) NationalityCount() (count int) {
 	return
 }
 
+// chipsText lists the chips by nationality in the language of the game's viewer.
+func (g *Game) chipsText(cs Chips) string {
+	var ss []string
+	for _, n := range nationalityValues() {
+		if count := cs[n]; count > 0 {
+			ss = append(ss, g.T("%d %s", count, n))
+		}
+	}
+	return g.toSentence(ss)
+}
+
 // RemainingChips provides the chips remaining after a player bid.
 func (p *Player) RemainingChips() (cs Chips) {
 	cs = make(Chips, len(p.Chips))
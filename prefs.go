@@ -0,0 +1,238 @@
+package tammany
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/datastore"
+	"github.com/gin-gonic/gin"
+)
+
+const prefsKind = "TammanyPrefs"
+
+// Prefs provides the notification preferences of a user.
+// A user preferring a daily digest receives no per-turn or end of game emails.
+// Notifications are delivered through Channel, which defaults to email,
+// posting to WebhookURL for channels other than email, in Language, which defaults to English.
+type Prefs struct {
+	Key          *datastore.Key `datastore:"__key__"`
+	Digest       bool
+	Channel      string
+	Language     string
+	WebhookURL   string `datastore:",noindex"`
+	LastDigestAt time.Time
+	UpdatedAt    time.Time
+}
+
+func prefsKey(uid int64) *datastore.Key {
+	return datastore.IDKey(prefsKind, uid, nil)
+}
+
+func newPrefs(uid int64) *Prefs {
+	return &Prefs{Key: prefsKey(uid)}
+}
+
+// UserID provides the id of the user having the preferences.
+func (p *Prefs) UserID() int64 {
+	return p.Key.ID
+}
+
+func (p *Prefs) view() gin.H {
+	channel := p.Channel
+	if channel == "" {
+		channel = emailChannel
+	}
+	return gin.H{"digest": p.Digest, "channel": channel, "webhookURL": p.WebhookURL, "language": p.language()}
+}
+
+// language provides the preferred language, if supported, or the default language.
+func (p *Prefs) language() string {
+	if supportedLanguage(p.Language) {
+		return p.Language
+	}
+	return defaultLanguage
+}
+
+// PrefsStore provides persistence of the preferences of users.
+type PrefsStore interface {
+	// GetPrefs returns the preferences of the user, or the default preferences if the user has none.
+	GetPrefs(*gin.Context, int64) (*Prefs, error)
+
+	// PutPrefs saves the preferences.
+	PutPrefs(*gin.Context, *Prefs) error
+
+	// DigestPrefs returns the preferences of the users preferring a daily digest.
+	DigestPrefs(*gin.Context) ([]*Prefs, error)
+}
+
+// WithPrefsStore sets the store used to persist preferences.
+func (client *Client) WithPrefsStore(s PrefsStore) *Client {
+	client.Prefs = s
+	return client
+}
+
+type dsPrefsStore struct {
+	*datastore.Client
+}
+
+func newDSPrefsStore(dsClient *datastore.Client) *dsPrefsStore {
+	return &dsPrefsStore{Client: dsClient}
+}
+
+func (s *dsPrefsStore) GetPrefs(c *gin.Context, uid int64) (*Prefs, error) {
+	p := newPrefs(uid)
+	err := s.Get(c, p.Key, p)
+	if err == datastore.ErrNoSuchEntity {
+		return p, nil
+	}
+	return p, err
+}
+
+func (s *dsPrefsStore) PutPrefs(c *gin.Context, p *Prefs) error {
+	p.UpdatedAt = time.Now()
+	_, err := s.Put(c, p.Key, p)
+	return err
+}
+
+func (s *dsPrefsStore) DigestPrefs(c *gin.Context) ([]*Prefs, error) {
+	q := datastore.NewQuery(prefsKind).Filter("Digest=", true)
+
+	var ps []*Prefs
+	_, err := s.GetAll(c, q, &ps)
+	return ps, err
+}
+
+// memoryPrefsStore provides an in-memory PrefsStore for local development and tests.
+type memoryPrefsStore struct {
+	mu    sync.Mutex
+	prefs map[int64]Prefs
+}
+
+// NewMemoryPrefsStore returns a PrefsStore that keeps preferences in memory.
+func NewMemoryPrefsStore() PrefsStore {
+	return &memoryPrefsStore{prefs: make(map[int64]Prefs)}
+}
+
+func (s *memoryPrefsStore) GetPrefs(c *gin.Context, uid int64) (*Prefs, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.prefs[uid]
+	if !ok {
+		return newPrefs(uid), nil
+	}
+	return &p, nil
+}
+
+func (s *memoryPrefsStore) PutPrefs(c *gin.Context, p *Prefs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p.UpdatedAt = time.Now()
+	s.prefs[p.UserID()] = *p
+	return nil
+}
+
+func (s *memoryPrefsStore) DigestPrefs(c *gin.Context) ([]*Prefs, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ps []*Prefs
+	for _, p := range s.prefs {
+		if p.Digest {
+			p2 := p
+			ps = append(ps, &p2)
+		}
+	}
+	return ps, nil
+}
+
+// showPrefs provides the preferences of the current user.
+func (client *Client) showPrefs(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil || cu == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You must be logged in to view your preferences."})
+			return
+		}
+
+		p, err := client.Prefs.GetPrefs(c, cu.ID())
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, p.view())
+	}
+}
+
+// updatePrefs updates the preferences of the current user.
+func (client *Client) updatePrefs(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil || cu == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You must be logged in to update your preferences."})
+			return
+		}
+
+		// Preferences absent from the request are left unchanged.
+		obj := struct {
+			Digest     *bool   `form:"digest" json:"digest"`
+			Channel    *string `form:"channel" json:"channel"`
+			WebhookURL *string `form:"webhookURL" json:"webhookURL"`
+			Language   *string `form:"language" json:"language"`
+		}{}
+
+		err = c.ShouldBind(&obj)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		p, err := client.Prefs.GetPrefs(c, cu.ID())
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if obj.Digest != nil {
+			p.Digest = *obj.Digest
+		}
+		if obj.Channel != nil {
+			p.Channel = *obj.Channel
+		}
+		if obj.WebhookURL != nil {
+			p.WebhookURL = *obj.WebhookURL
+		}
+		if obj.Language != nil {
+			if !supportedLanguage(*obj.Language) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unsupported language %q", *obj.Language)})
+				return
+			}
+			p.Language = *obj.Language
+		}
+
+		err = validateChannel(p.Channel, p.WebhookURL)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = client.Prefs.PutPrefs(c, p)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, p.view())
+	}
+}
@@ -0,0 +1,187 @@
+package tammany
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Projection provides the projected outcome were the elections of the current term held now.
+type Projection struct {
+	Year       int                 `json:"year"`
+	Term       int                 `json:"term"`
+	Wards      []*WardProjection   `json:"wards"`
+	Players    []*PlayerProjection `json:"players"`
+	MayorID    int                 `json:"mayorId"`
+	ChipAwards map[string][]int    `json:"chipAwards"`
+}
+
+// WardProjection provides the projected outcome of the election in a ward.
+// The projected winner is the candidate having the most influence when playing every playable favor chip.
+// The projected winner is secure if no other candidate can match its bosses, even when playing every playable chip.
+type WardProjection struct {
+	WardID    int         `json:"wardId"`
+	Influence map[int]int `json:"influence"`
+	WinnerID  int         `json:"winnerId"`
+	Tied      []int       `json:"tied,omitempty"`
+	Secure    bool        `json:"secure"`
+	VP        int         `json:"vp"`
+}
+
+// PlayerProjection provides the projected outcome for a player.
+type PlayerProjection struct {
+	PlayerID       int      `json:"playerId"`
+	Name           string   `json:"name"`
+	Wards          []int    `json:"wards"`
+	WardVP         int      `json:"wardVP"`
+	Mayor          bool     `json:"mayor"`
+	MayorVP        int      `json:"mayorVP"`
+	ChipAwards     []string `json:"chipAwards"`
+	Score          int      `json:"score"`
+	ProjectedScore int      `json:"projectedScore"`
+}
+
+func wardVP(wid wardID) int {
+	if wid == 14 {
+		return 2
+	}
+	return 1
+}
+
+// Projection projects the outcome of the elections of the current term given the current board.
+// The game is not modified.
+func (g *Game) Projection() *Projection {
+	pr := &Projection{
+		Year:       g.Year(),
+		Term:       g.Term(),
+		MayorID:    noPlayerID,
+		ChipAwards: make(map[string][]int),
+	}
+
+	pps := make(map[int]*PlayerProjection, len(g.Players()))
+	for _, p := range g.Players() {
+		pp := &PlayerProjection{PlayerID: p.ID(), Name: g.NameFor(p), Score: p.Score}
+		pr.Players = append(pr.Players, pp)
+		pps[p.ID()] = pp
+	}
+
+	// The projected board retains only the bosses of the projected winner of each ward,
+	// just as the board appears once the elections are resolved.
+	projected := g.projectedGame()
+	results := &electionResults{PlayerResults: make(playerResults, len(g.Players()))}
+	for _, p := range g.Players() {
+		results.PlayerResults[p.ID()] = new(playerResult)
+	}
+
+	for i, w := range g.ActiveWards() {
+		wp := g.projectWard(w)
+		pr.Wards = append(pr.Wards, wp)
+
+		pw := projected.ActiveWards()[i]
+		pw.Bosses = make(BossesMap)
+		if wp.WinnerID == noPlayerID {
+			continue
+		}
+
+		pw.Bosses[wp.WinnerID] = w.Bosses[wp.WinnerID]
+		result := results.PlayerResults[wp.WinnerID]
+		result.WardIDS = append(result.WardIDS, w.ID)
+		result.Score += wp.VP
+
+		pp := pps[wp.WinnerID]
+		pp.Wards = append(pp.Wards, int(w.ID))
+		pp.WardVP += wp.VP
+	}
+
+	if m := projected.newMayor(results); m != nil {
+		pr.MayorID = m.ID()
+		pps[m.ID()].Mayor = true
+		pps[m.ID()].MayorVP = mayorVP
+	}
+
+	for _, n := range g.Nationalities() {
+		for _, p := range projected.chipWinners(n) {
+			pr.ChipAwards[n.String()] = append(pr.ChipAwards[n.String()], p.ID())
+			pps[p.ID()].ChipAwards = append(pps[p.ID()].ChipAwards, n.String())
+		}
+	}
+
+	for _, pp := range pr.Players {
+		pp.ProjectedScore = pp.Score + pp.WardVP + pp.MayorVP
+	}
+	return pr
+}
+
+func (g *Game) projectWard(w *Ward) *WardProjection {
+	wp := &WardProjection{
+		WardID:    int(w.ID),
+		Influence: make(map[int]int),
+		WinnerID:  noPlayerID,
+		VP:        wardVP(w.ID),
+	}
+
+	var leaders Players
+	max := 0
+	for _, p := range g.Players() {
+		if w.BossesFor(p) == 0 {
+			continue
+		}
+
+		influence := p.MaxInfluenceIn(w)
+		wp.Influence[p.ID()] = influence
+		switch {
+		case influence > max:
+			max = influence
+			leaders = Players{p}
+		case influence == max:
+			leaders = append(leaders, p)
+		}
+	}
+
+	switch len(leaders) {
+	case 0:
+	case 1:
+		winner := leaders[0]
+		wp.WinnerID = winner.ID()
+		wp.Secure = true
+		for pid, influence := range wp.Influence {
+			if pid != winner.ID() && influence >= w.BossesFor(winner) {
+				wp.Secure = false
+			}
+		}
+	default:
+		for _, p := range leaders {
+			wp.Tied = append(wp.Tied, p.ID())
+		}
+	}
+	return wp
+}
+
+// projectedGame returns a copy of the game whose wards may be modified without modifying the game.
+// Players are shared with the game and must not be modified.
+func (g *Game) projectedGame() *Game {
+	s := *g.State
+	s.Wards = make(Wards, len(g.Wards))
+	for i, w := range g.Wards {
+		w2 := *w
+		s.Wards[i] = &w2
+	}
+
+	g2 := *g
+	g2.State = &s
+	return &g2
+}
+
+func (client *Client) projection(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		g := gameFrom(c)
+		if g == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": ErrGameNotFound.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, g.Projection())
+	}
+}
@@ -0,0 +1,274 @@
+package tammany
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/SlothNinja/codec"
+	"github.com/SlothNinja/contest"
+	"github.com/SlothNinja/log"
+	"github.com/SlothNinja/user"
+	"github.com/gin-gonic/gin"
+)
+
+// markPhaseBoundary notes that the current action crosses a phase boundary,
+// so the game is snapshot once the action is recorded.
+func (g *Game) markPhaseBoundary() {
+	g.phaseBoundary = true
+}
+
+// recordMove records a performed move, snapshotting the game if the move crossed a phase boundary.
+func (g *Game) recordMove(m *Move) {
+	g.Actions = append(g.Actions, m.String())
+	if g.phaseBoundary {
+		g.takeSnapshot()
+	}
+}
+
+// recordUpdate records the action posted to update by the user.
+func (g *Game) recordUpdate(c *gin.Context, cu *user.User) {
+	m, err := moveFromValues(c.Request.PostForm)
+	if err != nil {
+		log.Warningf("unable to record action of game %d: %v", g.ID(), err)
+		return
+	}
+
+	m.Year = g.Year()
+	if m.Action == bidAction {
+		m.WardID = g.CurrentWardID
+	}
+	if cp := g.CurrentPlayerFor(cu); cp != nil && adminActions[m.Action] == nil {
+		m.PlayerID = cp.ID()
+	}
+	g.recordMove(m)
+}
+
+// recordFinish records the finish of the turn of player p in the year.
+func (g *Game) recordFinish(p *Player, year int) {
+	m := newMove(finishAction)
+	m.Year = year
+	if p != nil {
+		m.PlayerID = p.ID()
+	}
+	g.recordMove(m)
+}
+
+// takeSnapshot snapshots the game, holding the snapshot until the game is saved.
+func (g *Game) takeSnapshot() {
+	g.phaseBoundary = false
+
+	snap, err := g.snapshot()
+	if err != nil {
+		log.Warningf("unable to snapshot game %d: %v", g.ID(), err)
+		return
+	}
+	g.snapshots = append(g.snapshots, snap)
+}
+
+// snapshotsOf provides the saved snapshots of the game and those taken since the game was saved, ordered by log length.
+func (client *Client) snapshotsOf(c *gin.Context, g *Game) ([]*Snapshot, error) {
+	saved, err := client.Store.Snapshots(c, g)
+	if err != nil {
+		return nil, err
+	}
+
+	byLogLength := make(map[int]*Snapshot)
+	for _, snap := range append(saved, g.snapshots...) {
+		byLogLength[snap.LogLength] = snap
+	}
+
+	snaps := make([]*Snapshot, 0, len(byLogLength))
+	for _, snap := range byLogLength {
+		snaps = append(snaps, snap)
+	}
+	sort.Sort(bySnapshotLogLength(snaps))
+	return snaps, nil
+}
+
+// nearestSnapshot provides the latest of the snapshots taken within the first n recorded actions.
+func nearestSnapshot(snaps []*Snapshot, n int) *Snapshot {
+	var nearest *Snapshot
+	for _, snap := range snaps {
+		if snap.Actions <= n {
+			nearest = snap
+		}
+	}
+	return nearest
+}
+
+// restoreSnapshot provides a copy of the game as it stood when the snapshot was taken.
+// The game itself is unchanged.
+func (client *Client) restoreSnapshot(c *gin.Context, g *Game, snap *Snapshot) (*Game, error) {
+	if snap.Actions > len(g.Actions) || snap.LogLength > len(g.Log) || snap.Draws > len(g.Draws) {
+		return nil, fmt.Errorf("snapshot at log entry %d of game %d exceeds the recorded actions, draws or log", snap.LogLength, g.ID())
+	}
+
+	// Copy the game log, so the restored game shares no entries with the game.
+	bs, err := encodeState(g.State)
+	if err != nil {
+		return nil, err
+	}
+
+	base, _, err := decodeState(bs)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := snap.decodeState()
+	if err != nil {
+		return nil, err
+	}
+
+	s.Log = base.Log[:snap.LogLength]
+	s.Actions = base.Actions[:snap.Actions]
+	s.Draws = base.Draws[:snap.Draws]
+
+	// Admin edits rolled back after the snapshot had yet to be rolled back.
+	for _, e := range base.Log[snap.LogLength:] {
+		if ae, ok := e.(*adminEditEntry); ok && ae.RollbackOf >= 0 && ae.RollbackOf < snap.LogLength {
+			if rolledBack, ok := s.Log[ae.RollbackOf].(*adminEditEntry); ok {
+				rolledBack.RolledBack = false
+			}
+		}
+	}
+
+	g2 := New(c, g.ID())
+	bs, err = codec.Encode(g.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	err = codec.Decode(g2.Header, bs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The saved state is that of the game as it stands, lest the restored game be archived or viewed as such.
+	g2.SavedState = nil
+	g2.restoreHeader(snap)
+	g2.State = s
+	g2.redraws = append([]nationality(nil), base.Draws[snap.Draws:]...)
+
+	err = client.init(c, g2)
+	if err != nil {
+		return nil, err
+	}
+	return g2, nil
+}
+
+// Replay provides a copy of the game as it stood after the first n recorded actions,
+// restoring the nearest snapshot of the game and re-applying the subsequent actions.
+// The game itself is unchanged.
+func (client *Client) Replay(c *gin.Context, g *Game, n int) (*Game, error) {
+	if n < 0 || n > len(g.Actions) {
+		return nil, fmt.Errorf("unable to replay %d actions, since game %d recorded %d actions", n, g.ID(), len(g.Actions))
+	}
+
+	snaps, err := client.snapshotsOf(c, g)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := nearestSnapshot(snaps, n)
+	if snap == nil {
+		return nil, fmt.Errorf("game %d has no snapshot from which to replay %d actions", g.ID(), n)
+	}
+	return client.replayFrom(c, g, snap, n)
+}
+
+// replayError reports the recorded action of a game that failed to replay.
+type replayError struct {
+	GameID int64
+	Index  int
+	Action string
+	Err    error
+}
+
+func (e *replayError) Error() string {
+	return fmt.Sprintf("unable to replay action %d %q of game %d: %v", e.Index, e.Action, e.GameID, e.Err)
+}
+
+func (e *replayError) Unwrap() error {
+	return e.Err
+}
+
+// replayFrom provides a copy of the game as it stood after the first n recorded actions,
+// restoring the snapshot and re-applying the subsequent actions.
+func (client *Client) replayFrom(c *gin.Context, g *Game, snap *Snapshot, n int) (*Game, error) {
+	g2, err := client.restoreSnapshot(c, g, snap)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := snap.Actions; i < n; i++ {
+		m, err := ParseMove(g.Actions[i])
+		if err == nil {
+			err = client.applyMove(c, g2, m)
+		}
+		if err != nil {
+			return nil, &replayError{GameID: g.ID(), Index: i, Action: g.Actions[i], Err: err}
+		}
+	}
+	return g2, nil
+}
+
+// applyMove performs and records the move, as if its values were posted to update or finish.
+func (client *Client) applyMove(c *gin.Context, g *Game, m *Move) error {
+	cu, err := g.moveUser(m)
+	if err != nil {
+		return err
+	}
+
+	vs := m.Values()
+	if m.Action == finishAction {
+		// Finishing a turn is recorded only once the player confirmed any office warning.
+		vs.Set("action", "confirm-finish")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(vs.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	c2 := c.Copy()
+	c2.Request = req
+	user.StatsWith(c2, new(user.Stats))
+
+	switch m.Action {
+	case undoAction, redoAction, resetAction:
+		return fmt.Errorf("%s may not be replayed", m.Action)
+	case finishAction:
+		var cs []*contest.Contest
+		_, cs, err = client.finishTurn(c2, g, cu)
+		if err == nil && cs != nil {
+			g.endGame()
+		}
+	default:
+		_, _, err = g.update(c2, cu)
+	}
+	if err != nil {
+		return err
+	}
+
+	g.recordMove(m)
+	return nil
+}
+
+// moveUser provides the user performing the move: an admin for admin actions, and otherwise the user of the player of the move.
+func (g *Game) moveUser(m *Move) (*user.User, error) {
+	if adminActions[m.Action] != nil {
+		u := user.New(0)
+		u.Name = "admin"
+		u.Admin = true
+		return u, nil
+	}
+
+	p := g.PlayerByID(m.PlayerID)
+	if p == nil {
+		return nil, fmt.Errorf("missing player for %q", m)
+	}
+	return p.User(), nil
+}
@@ -0,0 +1,93 @@
+package tammany
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var createdAtRE = regexp.MustCompile(`"CreatedAtF":"[^"]*"`)
+
+// turnMoves provides the moves of a turn of the actions phase, in which the current player places
+// a boss and an immigrant from the Castle Garden in the first active ward.
+func turnMoves(g *Game) []*Move {
+	cp := g.CurrentPlayer()
+
+	place := newMove(placePiecesAction)
+	place.Year, place.PlayerID = g.Year(), cp.ID()
+	place.WardID, place.Bosses, place.Immigrant = g.ActiveWards()[0].ID, 1, noNationality
+	for _, n := range g.Nationalities() {
+		if g.CastleGarden[n] > 0 {
+			place.Immigrant = n
+		}
+	}
+
+	finish := newMove(finishAction)
+	finish.Year, finish.PlayerID = g.Year(), cp.ID()
+	return []*Move{place, finish}
+}
+
+// gameView describes the game for comparison, omitting the times of its log entries.
+func gameView(t *testing.T, g *Game) string {
+	s := *g.State
+	s.Log, s.Actions = nil, nil
+	bs, err := encodeState(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	texts := make([]string, len(g.Log))
+	for i, e := range g.Log {
+		texts[i] = e.Text(g)
+	}
+	return fmt.Sprintf("%v %v %d %v %v\n%s\n%s", g.Phase, g.SubPhase, g.Round, g.CPUserIndices, g.Status,
+		strings.Join(texts, "\n"), createdAtRE.ReplaceAll(bs, nil))
+}
+
+func TestReplayMatchesLiveGame(t *testing.T) {
+	client, c := newTestClient(), testContext()
+	g := newTestGame(t, 3)
+
+	views := []string{gameView(t, g)}
+	for turn := 0; turn < 6; turn++ {
+		for _, m := range turnMoves(g) {
+			err := client.applyMove(c, g, m)
+			if err != nil {
+				t.Fatalf("turn %d: %q: %v", turn, m, err)
+			}
+			views = append(views, gameView(t, g))
+		}
+
+		// Replay from both saved snapshots and those taken since.
+		if turn == 2 {
+			client.putSnapshots(c, g)
+		}
+	}
+
+	if g.Year() != 3 {
+		t.Fatalf("got year %d, want year 3", g.Year())
+	}
+
+	snaps, err := client.snapshotsOf(c, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 3 {
+		t.Errorf("got %d snapshots, want one at the start and one at the start of each later year", len(snaps))
+	}
+
+	for n := range g.Actions {
+		g2, err := client.Replay(c, g, n)
+		if err != nil {
+			t.Fatalf("replay of %d actions: %v", n, err)
+		}
+		if got := gameView(t, g2); got != views[n] {
+			t.Errorf("replay of %d actions:\ngot  %s\nwant %s", n, got, views[n])
+		}
+	}
+
+	if _, err := client.Replay(c, g, len(g.Actions)+1); err == nil {
+		t.Errorf("replay of more actions than recorded succeeded")
+	}
+}
@@ -0,0 +1,264 @@
+package tammany
+
+import (
+	"encoding/gob"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SlothNinja/game"
+	"github.com/SlothNinja/restful"
+	"github.com/SlothNinja/user"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gob.Register(new(rewoundEntry))
+	registerEntry("rewound", new(rewoundEntry))
+}
+
+// rewoundEntry records that an admin rewound the game to the state immediately after the entry having index EntryIndex,
+// removing the Removed later entries from the log.
+type rewoundEntry struct {
+	*Entry
+	AdminID    int64
+	AdminName  string
+	Reason     string
+	EntryIndex int
+	Removed    int
+}
+
+// RewindPoint identifies an entry of the game log to which an admin may rewind the game.
+type RewindPoint struct {
+	EntryIndex int       `json:"entryIndex"`
+	Entry      string    `json:"entry"`
+	SavedAt    time.Time `json:"savedAt"`
+}
+
+// rewindPoints provides the entries of the game log to which the game may be rewound,
+// being those immediately preceding a snapshot of the game.
+func (g *Game) rewindPoints(snaps []*Snapshot) []*RewindPoint {
+	savedAt := make(map[int]time.Time)
+	for _, snap := range snaps {
+		savedAt[snap.LogLength] = snap.CreatedAt
+	}
+
+	var ps []*RewindPoint
+	for i := 0; i < len(g.Log)-1; i++ {
+		if t, ok := savedAt[i+1]; ok {
+			ps = append(ps, &RewindPoint{EntryIndex: i, Entry: g.Log[i].Text(g), SavedAt: t})
+		}
+	}
+	return ps
+}
+
+// rewind restores the game to the snapshot taken immediately after the entry having index i,
+// truncating the later entries of the log and recorded actions, and logging the rewind.
+// Games that ended are not rewound, as their results are already rated and recorded.
+// The game retains its key and update time, so saving the rewound game fails if the game changed in the meantime.
+func (client *Client) rewind(c *gin.Context, g *Game, cu *user.User, snaps []*Snapshot, i int, reason string) (*rewoundEntry, error) {
+	reason = strings.TrimSpace(reason)
+	switch {
+	case !cu.IsAdmin():
+		return nil, g.verror(ErrNotAdmin, "Only an admin may rewind the game.")
+	case reason == "":
+		return nil, g.verror(ErrMissingReason, "A reason is required to rewind the game.")
+	case g.Status != game.Running:
+		return nil, g.verror(ErrGameNotRunning, "Only a running game may be rewound.")
+	case i < 0 || i >= len(g.Log)-1:
+		return nil, g.verror(ErrInvalidEntry, "Unable to rewind to entry %d, since the log has %d entries.", i, len(g.Log))
+	}
+
+	var snap *Snapshot
+	for _, s := range snaps {
+		if s.LogLength == i+1 {
+			snap = s
+		}
+	}
+
+	if snap == nil {
+		return nil, g.verror(ErrNoSnapshot, "No saved state exists for entry %d.", i)
+	}
+
+	removed := len(g.Log) - (i + 1)
+	g2, err := client.restoreSnapshot(c, g, snap)
+	if err != nil {
+		return nil, err
+	}
+	g.restoreHeader(snap)
+	g.State = g2.State
+
+	err = client.init(c, g)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &rewoundEntry{
+		Entry:      g.newEntry(),
+		AdminID:    cu.ID(),
+		AdminName:  cu.Name,
+		Reason:     reason,
+		EntryIndex: i,
+		Removed:    removed,
+	}
+	g.Log = append(g.Log, e)
+	return e, nil
+}
+
+func (e *rewoundEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *rewoundEntry) Text(g *Game) string {
+	return g.T("Admin %s rewound the game to entry %d, removing %d later entries. Reason: %s",
+		e.AdminName, e.EntryIndex, e.Removed, e.Reason)
+}
+
+func (e *rewoundEntry) Event() *Event {
+	ev := e.event(e)
+	ev.Reason = e.Reason
+	return ev
+}
+
+// sendRewindNotifications notifies the players that an admin rewound the game.
+func (client *Client) sendRewindNotifications(c *gin.Context, g *Game, e *rewoundEntry) error {
+	var result error
+	for _, p := range g.Players() {
+		err := client.notify(c, g, p, func(lang string) (*Notification, error) {
+			return g.rewindNotification(c, e, lang)
+		})
+		if err != nil {
+			client.Log.Warningf("unable to notify %s of rewind of game %d: %v", g.NameFor(p), g.ID(), err)
+			result = err
+		}
+	}
+	return result
+}
+
+func (g *Game) rewindNotification(c *gin.Context, e *rewoundEntry, lang string) (*Notification, error) {
+	g = g.withLanguage(lang)
+	note := g.newNotification(c, rewindNotice)
+
+	var names []string
+	for _, p := range g.CurrentPlayers() {
+		names = append(names, g.NameFor(p))
+	}
+
+	err := renderNotification(note, lang, rewindNotice, struct {
+		GameID  int64
+		Title   string
+		Link    string
+		Rewound string
+		Current string
+	}{g.ID(), g.Title, note.Link, e.Text(g), g.toSentence(names)})
+	return note, err
+}
+
+// loadForRewind loads the game, as saved, and its snapshots.
+func (client *Client) loadForRewind(c *gin.Context) (*Game, []*Snapshot, int, error) {
+	cu, err := client.User.Current(c)
+	if err != nil || !cu.IsAdmin() {
+		return nil, nil, http.StatusForbidden, fmt.Errorf("Only an admin may rewind the game.")
+	}
+
+	id, err := getID(c)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, err
+	}
+
+	g := New(c, id)
+	err = client.dsGet(c, g)
+	if err != nil {
+		return nil, nil, http.StatusNotFound, err
+	}
+
+	snaps, err := client.Store.Snapshots(c, g)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+	return g, snaps, http.StatusOK, nil
+}
+
+// rewindPointsFor provides the entries of the game log to which an admin may rewind the game.
+func (client *Client) rewindPointsFor(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		g, snaps, status, err := client.loadForRewind(c)
+		if err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"points": g.rewindPoints(snaps)})
+	}
+}
+
+// rewindGame rewinds the game to the state immediately after a chosen entry of the game log and notifies the players.
+func (client *Client) rewindGame(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		g, snaps, status, err := client.loadForRewind(c)
+		if err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		cu, err := client.User.Current(c)
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		obj := struct {
+			Entry  int    `form:"entry" json:"entry"`
+			Reason string `form:"reason" json:"reason"`
+		}{Entry: -1}
+
+		err = c.ShouldBind(&obj)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		e, err := client.rewind(c, g, cu, snaps, obj.Entry, obj.Reason)
+		if isValidationError(err) {
+			c.JSON(http.StatusUnprocessableEntity, validationJSON(err))
+			return
+		}
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = client.save(c, g, cu)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Player caches may hold moves made after the entry.
+		for _, p := range g.Players() {
+			client.Cache.Delete(g.UndoKey(p.User()))
+		}
+
+		// Snapshots after the entry belong to the discarded moves, so remove them.
+		err = client.Store.DeleteSnapshots(c, g, obj.Entry+1)
+		if err != nil {
+			client.Log.Warningf(err.Error())
+		}
+
+		err = client.sendRewindNotifications(c, g, e)
+		if err != nil {
+			client.Log.Warningf(err.Error())
+		}
+
+		c.JSON(http.StatusOK, gin.H{"rewound": e.Event(), "log": len(g.Log)})
+	}
+}
@@ -0,0 +1,48 @@
+package tammany
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SlothNinja/game"
+)
+
+func TestRewind(t *testing.T) {
+	client, c := newTestClient(), testContext()
+	g := newTestGame(t, 3)
+	playTurns(t, client, g, 6)
+	client.putSnapshots(c, g)
+
+	snaps, err := client.Store.Snapshots(c, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := snaps[1].LogLength - 1
+
+	e, err := client.rewind(c, g, testAdmin(), snaps, i, "misclick")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Log) != i+2 || g.Log[i+1] != e || len(g.Actions) != snaps[1].Actions || g.Status != game.Running {
+		t.Errorf("got %d entries and %d actions, want the game as after entry %d", len(g.Log), len(g.Actions), i)
+	}
+}
+
+func TestRewindOfEndedGame(t *testing.T) {
+	client, c := newTestClient(), testContext()
+	g := newTestGame(t, 3)
+	playTurns(t, client, g, 6)
+	client.putSnapshots(c, g)
+
+	snaps, err := client.Store.Snapshots(c, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.Status = game.Completed
+	n := len(g.Log)
+	_, err = client.rewind(c, g, testAdmin(), snaps, snaps[1].LogLength-1, "misclick")
+	if !errors.Is(err, ErrGameNotRunning) || len(g.Log) != n || g.Status != game.Completed {
+		t.Errorf("got error %v, want the ended game left as is", err)
+	}
+}
@@ -1,6 +1,8 @@
 package tammany
 
 import (
+	"os"
+
 	"github.com/SlothNinja/game"
 	"github.com/SlothNinja/mlog"
 	"github.com/SlothNinja/rating"
@@ -11,10 +13,13 @@ import (
 
 type Client struct {
 	*sn.Client
-	User   *user.Client
-	Game   *game.Client
-	MLog   *mlog.Client
-	Rating *rating.Client
+	User    *user.Client
+	Game    *game.Client
+	MLog    *mlog.Client
+	Rating  *rating.Client
+	Store   GameStore
+	Prefs   PrefsStore
+	Follows FollowStore
 }
 
 func NewClient(snClient *sn.Client, uClient *user.Client, gClient *game.Client, rClient *rating.Client, t gtype.Type) *Client {
@@ -25,7 +30,7 @@ func NewClient(snClient *sn.Client, uClient *user.Client, gClient *game.Client,
 		MLog:   mlog.NewClient(snClient, uClient),
 		Rating: rClient,
 	}
-	return client.register(t)
+	return client.withStoresFrom(os.Getenv(storeEnv)).register(t)
 }
 
 // AddRoutes addes routing for game.
@@ -47,6 +52,7 @@ func (client *Client) addRoutes(prefix string) *Client {
 	g.GET("/show/:hid",
 		client.fetch,
 		game.SetAdmin(false),
+		client.spectate,
 		client.show(prefix),
 	)
 
@@ -89,6 +95,51 @@ func (client *Client) addRoutes(prefix string) *Client {
 		client.addMessage(prefix),
 	)
 
+	// Export
+	g.GET("/export/:hid",
+		client.exportGame(prefix),
+	)
+
+	// Log
+	g.GET("/log/:hid",
+		client.exportLog(prefix),
+	)
+
+	// Log Events
+	g.GET("/events/:hid",
+		client.events(prefix),
+	)
+
+	// Projection
+	g.GET("/projection/:hid",
+		client.fetch,
+		client.spectate,
+		client.projection(prefix),
+	)
+
+	// What If
+	g.GET("/whatif/:hid",
+		client.fetch,
+		client.spectate,
+		client.whatIf(prefix),
+	)
+
+	// Follow
+	g.PUT("/follow/:hid",
+		client.fetch,
+		client.follow(prefix),
+	)
+
+	// Unfollow
+	g.DELETE("/follow/:hid",
+		client.unfollow(prefix),
+	)
+
+	// Spectator Delay
+	g.PUT("/spectators/:hid",
+		client.setSpectatorDelay(prefix),
+	)
+
 	// Games Group
 	gs := client.Router.Group(prefix + "/games")
 
@@ -103,6 +154,83 @@ func (client *Client) addRoutes(prefix string) *Client {
 		client.jsonIndexAction(prefix),
 	)
 
+	// API Group
+	api := client.Router.Group(prefix + "/api")
+
+	// Games of current user
+	api.GET("/games/:status",
+		client.withCurrentUID,
+		client.Game.GetFiltered(gtype.Tammany),
+		client.apiIndex(prefix),
+	)
+
+	// Game State
+	api.GET("/game/:hid",
+		client.fetch,
+		game.SetAdmin(false),
+		client.spectate,
+		client.apiShow(prefix),
+	)
+
+	// Text Board
+	api.GET("/game/:hid/board.txt",
+		client.fetch,
+		game.SetAdmin(false),
+		client.spectate,
+		client.textBoard(prefix),
+	)
+
+	// SVG Board
+	api.GET("/game/:hid/board.svg",
+		client.fetch,
+		game.SetAdmin(false),
+		client.spectate,
+		client.svgBoard(prefix),
+	)
+
+	// Move
+	api.POST("/game/:hid/move",
+		client.apiMoveValues,
+		client.fetch,
+		game.SetAdmin(false),
+		client.User.StatsFetch,
+		client.apiMove(prefix),
+	)
+
+	// Preferences Group
+	prefs := client.Router.Group(prefix + "/prefs")
+
+	// Show
+	prefs.GET("",
+		client.showPrefs(prefix),
+	)
+
+	// Update
+	prefs.PUT("",
+		client.updatePrefs(prefix),
+	)
+
+	// Follows Group
+	follows := client.Router.Group(prefix + "/follows")
+
+	// Games followed by current user
+	follows.GET("",
+		client.followed(prefix),
+	)
+
+	// Stats Group
+	stats := client.Router.Group(prefix + "/stats")
+
+	// Show
+	stats.GET("/:uid",
+		client.showStats(prefix),
+	)
+
+	// JSON
+	stats.GET("/:uid/json",
+		client.jsonStats(prefix),
+	)
+
 	// Admin Group
 	admin := g.Group("/admin")
 
@@ -126,5 +254,57 @@ func (client *Client) addRoutes(prefix string) *Client {
 		client.update(prefix),
 	)
 
+	// Admin Edit Targets
+	admin.GET("/:hid/edit",
+		client.fetch,
+		game.SetAdmin(true),
+		client.adminViews(prefix),
+	)
+
+	// Admin Edit
+	admin.PUT("/:hid/edit",
+		client.fetch,
+		game.SetAdmin(true),
+		client.applyAdminEdits(prefix),
+	)
+
+	// Admin Tools Group
+	tools := client.Router.Group(prefix + "/admin")
+
+	// Migrate saved state of all games to current schema version
+	tools.POST("/migrate",
+		client.migrateStates(prefix),
+	)
+
+	// Import game from archive
+	tools.POST("/import",
+		client.importGame(prefix),
+	)
+
+	// Audit all running and completed games
+	tools.POST("/audit",
+		client.auditGames(prefix),
+	)
+
+	// Audit game
+	tools.GET("/audit/:hid",
+		client.auditGame(prefix),
+	)
+
+	// Log entries to which game may be rewound
+	tools.GET("/rewind/:hid",
+		client.rewindPointsFor(prefix),
+	)
+
+	// Rewind game to log entry
+	tools.POST("/rewind/:hid",
+		client.rewindGame(prefix),
+	)
+
+	// Send daily digests
+	tools.GET("/digest",
+		client.sendDigests(prefix),
+	)
+
 	return client
 }
@@ -0,0 +1,132 @@
+package tammany
+
+import (
+	"bytes"
+	"html/template"
+	"sort"
+
+	"github.com/SlothNinja/log"
+)
+
+// TermScore provides the victory points scored by a player at the end of a term.
+type TermScore struct {
+	Year        int
+	Wards       []int
+	Ward14Bonus int
+	Mayor       bool
+}
+
+// WardVP provides the victory points scored for wards won during the term, excluding the ward 14 bonus.
+func (ts *TermScore) WardVP() int {
+	return len(ts.Wards)
+}
+
+// ScoreBreakdown provides the victory points scored by a player by source, compiled from the game log.
+type ScoreBreakdown struct {
+	PlayerID       int
+	Name           string
+	Terms          []*TermScore
+	ChipMajorities []string
+	VP             VPBreakdown
+	Score          int
+}
+
+// ScoreBreakdowns compiles the score breakdown of each player from the game log, highest total first.
+func (g *Game) ScoreBreakdowns() []*ScoreBreakdown {
+	sbs := make([]*ScoreBreakdown, len(g.Players()))
+	byID := make(map[int]*ScoreBreakdown, len(g.Players()))
+	for i, p := range g.Players() {
+		sbs[i] = &ScoreBreakdown{PlayerID: p.ID(), Name: g.NameFor(p), Score: p.Score}
+		byID[p.ID()] = sbs[i]
+	}
+
+	for _, ev := range g.Log.Events() {
+		switch ev.Kind {
+		case "scoreVP":
+			for _, sb := range sbs {
+				ts := &TermScore{Year: ev.Year}
+				if pe, ok := ev.Players[sb.PlayerID]; ok {
+					ts.Wards = pe.Wards
+					ts.Ward14Bonus = pe.Score - len(pe.Wards)
+					ts.Mayor = pe.Mayor
+				}
+				sb.Terms = append(sb.Terms, ts)
+				sb.VP.Wards += ts.WardVP()
+				sb.VP.Ward14Bonus += ts.Ward14Bonus
+				if ts.Mayor {
+					sb.VP.Mayor += mayorVP
+				}
+			}
+		case "awardFavorChipPoints":
+			if sb, ok := byID[ev.PlayerID]; ok {
+				sb.ChipMajorities = append(sb.ChipMajorities, ev.Chip)
+				sb.VP.ChipMajorities += ev.Points
+			}
+		case "awardSlanderChipPoints":
+			if sb, ok := byID[ev.PlayerID]; ok {
+				sb.VP.SlanderChips += ev.Points
+			}
+		}
+	}
+
+	sort.SliceStable(sbs, func(i, j int) bool { return sbs[i].VP.Total() > sbs[j].VP.Total() })
+	return sbs
+}
+
+var scoreBreakdownTemplate = template.Must(template.New("score_breakdown").Parse(`
+<table class="score-breakdown">
+	<thead>
+		<tr>
+			<th>Player</th>
+			{{- range .Years}}
+			<th>Year {{.}} Wards</th>
+			{{- end}}
+			<th>Ward VP</th>
+			<th>Ward 14 Bonus</th>
+			<th>Mayor</th>
+			<th>Chip Majorities</th>
+			<th>Slander Chips</th>
+			<th>Total</th>
+		</tr>
+	</thead>
+	<tbody>
+		{{- range .Breakdowns}}
+		<tr>
+			<td>{{.Name}}</td>
+			{{- range .Terms}}
+			<td>{{len .Wards}}{{if .Mayor}} (Mayor){{end}}</td>
+			{{- end}}
+			<td>{{.VP.Wards}}</td>
+			<td>{{.VP.Ward14Bonus}}</td>
+			<td>{{.VP.Mayor}}</td>
+			<td>{{.VP.ChipMajorities}}{{range $i, $n := .ChipMajorities}}{{if $i}}, {{else}} ({{end}}{{$n}}{{end}}{{if .ChipMajorities}}){{end}}</td>
+			<td>{{.VP.SlanderChips}}</td>
+			<td>{{.VP.Total}}</td>
+		</tr>
+		{{- end}}
+	</tbody>
+</table>
+`))
+
+// ScoreBreakdownHTML renders the score breakdowns of the players as an HTML table.
+func (g *Game) ScoreBreakdownHTML() template.HTML {
+	sbs := g.ScoreBreakdowns()
+
+	var years []int
+	if len(sbs) > 0 {
+		for _, ts := range sbs[0].Terms {
+			years = append(years, ts.Year)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	err := scoreBreakdownTemplate.Execute(buf, map[string]interface{}{
+		"Years":      years,
+		"Breakdowns": sbs,
+	})
+	if err != nil {
+		log.Errorf(err.Error())
+		return ""
+	}
+	return template.HTML(buf.String())
+}
@@ -2,7 +2,6 @@ package tammany
 
 import (
 	"github.com/SlothNinja/game"
-	"github.com/SlothNinja/sn"
 	"github.com/gin-gonic/gin"
 )
 
@@ -41,7 +40,7 @@ func (g *Game) selectArea(c *gin.Context) (tmpl string, act game.ActionType, err
 	} else if o := g.getOffice(c); o != noOffice {
 		tmpl, act = "tammany/assign_office_dialog", game.None
 	} else {
-		tmpl, act, err = "tammany/flash_notice", game.None, sn.NewVError("Invalid area selected.")
+		tmpl, act, err = "tammany/flash_notice", game.None, g.verror(ErrInvalidValue, "Invalid area selected.")
 	}
 	return
 }
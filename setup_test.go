@@ -0,0 +1,76 @@
+package tammany
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SlothNinja/game"
+	"github.com/SlothNinja/log"
+	"github.com/SlothNinja/sn"
+	"github.com/SlothNinja/user"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	log.DefaultLevel = log.LvlNone
+	os.Exit(m.Run())
+}
+
+// newTestClient provides a client keeping games in memory.
+func newTestClient() *Client {
+	client := &Client{
+		Client: &sn.Client{Log: new(log.Logger)},
+		Game:   &game.Client{},
+	}
+	return client.withStoresFrom("memory")
+}
+
+func testContext() *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	return c
+}
+
+// formContext provides a context whose request posts the form values.
+func formContext(t *testing.T, vs url.Values) *gin.Context {
+	req, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(vs.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	c := testContext()
+	c.Request = req
+	return c
+}
+
+// newTestGame provides a started game of n players, the users of which have ids 1 through n.
+func newTestGame(t *testing.T, n int) *Game {
+	c := testContext()
+	g := New(c, 1)
+	g.Title = "test"
+	g.NumPlayers = n
+	for i := 1; i <= n; i++ {
+		g.UserIDS = append(g.UserIDS, int64(i))
+		g.UserNames = append(g.UserNames, fmt.Sprintf("player%d", i))
+		g.UserEmails = append(g.UserEmails, fmt.Sprintf("player%d@example.com", i))
+	}
+	g.start(c)
+	g.Header.AfterLoad()
+	for _, p := range g.Players() {
+		p.init(g)
+	}
+	return g
+}
+
+func testAdmin() *user.User {
+	u := user.New(100)
+	u.Name = "admin"
+	u.Admin = true
+	return u
+}
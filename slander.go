@@ -8,14 +8,15 @@ import (
 	"github.com/SlothNinja/game"
 	"github.com/SlothNinja/log"
 	"github.com/SlothNinja/restful"
-	"github.com/SlothNinja/sn"
 	"github.com/SlothNinja/user"
 	"github.com/gin-gonic/gin"
 )
 
 func init() {
 	gob.RegisterName("*game.firstSlanderEntry", new(firstSlanderEntry))
+	registerEntry("firstSlander", new(firstSlanderEntry))
 	gob.RegisterName("*game.secondSlanderEntry", new(secondSlanderEntry))
+	registerEntry("secondSlander", new(secondSlanderEntry))
 }
 
 // SlanderedPlayer returns the player that was slandered.
@@ -89,10 +90,22 @@ func (g *Game) newFirstSlanderEntryFor(p *Player, w *Ward, op *Player, n nationa
 }
 
 func (e *firstSlanderEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	return restful.HTML("%s used an %s favor to slander %s in ward %d",
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *firstSlanderEntry) Text(g *Game) string {
+	return g.T("%s used an %s favor to slander %s in ward %d.",
 		g.NameByPID(e.PlayerID), e.Chip, g.NameByPID(e.OtherPlayerID), e.WardID)
 }
 
+func (e *firstSlanderEntry) Event() *Event {
+	ev := e.event(e)
+	ev.WardID = int(e.WardID)
+	ev.Chip = e.Chip.eventString()
+	ev.Chips = 1
+	return ev
+}
+
 type secondSlanderEntry struct {
 	*Entry
 	WardID wardID
@@ -111,53 +124,65 @@ func (g *Game) newSecondSlanderEntryFor(p *Player, w *Ward, op *Player, n nation
 }
 
 func (e *secondSlanderEntry) HTML(c *gin.Context, g *Game, cu *user.User) template.HTML {
-	return restful.HTML("%s used two %s favors to slander %s in ward %d",
+	return restful.HTML("%s", template.HTMLEscapeString(e.Text(g)))
+}
+
+func (e *secondSlanderEntry) Text(g *Game) string {
+	return g.T("%s used two %s favors to slander %s in ward %d.",
 		g.NameByPID(e.PlayerID), e.Chip, g.NameByPID(e.OtherPlayerID), e.WardID)
 }
 
+func (e *secondSlanderEntry) Event() *Event {
+	ev := e.event(e)
+	ev.WardID = int(e.WardID)
+	ev.Chip = e.Chip.eventString()
+	ev.Chips = 2
+	return ev
+}
+
 func (g *Game) validateSlander(c *gin.Context, cu *user.User) (*Player, *Ward, nationality, error) {
 	nInt, err := strconv.Atoi(c.PostForm("slander-nationality"))
 	if err != nil {
 		log.Debugf(err.Error())
-		return nil, nil, noNationality, sn.NewVError("you must select a chip to play")
+		return nil, nil, noNationality, g.verror(ErrNoChipSelected, "you must select a chip to play")
 	}
 
 	w, cp, n, p := g.getWard(c), g.CurrentPlayer(), nationality(nInt), g.playerBySID(c.PostForm("slandered-player"))
 	switch {
 	case !g.IsCurrentPlayer(cu):
-		return nil, nil, noNationality, sn.NewVError("Only the current player can slander another player.")
+		return nil, nil, noNationality, g.verror(ErrNotCurrentPlayer, "Only the current player can slander another player.")
 	case n == noNationality:
-		return nil, nil, noNationality, sn.NewVError("You must select a favor chip with which to slandar.")
+		return nil, nil, noNationality, g.verror(ErrNoChipSelected, "You must select a favor chip with which to slandar.")
 	case w == nil:
-		return nil, nil, noNationality, sn.NewVError("You must first select a ward.")
+		return nil, nil, noNationality, g.verror(ErrNoWardSelected, "You must first select a ward.")
 	case w.LockedUp:
-		return nil, nil, noNationality, sn.NewVError("You can't slander a player in locked ward.")
+		return nil, nil, noNationality, g.verror(ErrWardLocked, "You can't slander a player in locked ward.")
 	case w.Immigrants[n] < 1:
-		return nil, nil, noNationality, sn.NewVError("You attempted to slander with a %s chip, but there are no %s immigrants in the selected ward.", n, n)
+		return nil, nil, noNationality, g.verror(ErrNoImmigrant, "You attempted to slander with a %s chip, but there are no %s immigrants in the selected ward.", n, n)
 	case cp.placedPieces() == 1:
-		return nil, nil, noNationality, sn.NewVError("You are in the process of placing pieces (immigrants and/or bosses).  You must use office before or after placing pieces, but not during.")
+		return nil, nil, noNationality, g.verror(ErrPlacingPieces, "You are in the process of placing pieces (immigrants and/or bosses).  You must use office before or after placing pieces, but not during.")
 	case g.Phase != actions:
-		return nil, nil, noNationality, sn.NewVError("Wrong phase for performing this action.")
+		return nil, nil, noNationality, g.verror(ErrWrongPhase, "Wrong phase for performing this action.")
 	case g.Term() < 2:
-		return nil, nil, noNationality, sn.NewVError("You can't slander in term %d.", g.Term())
+		return nil, nil, noNationality, g.verror(ErrWrongPhase, "You can't slander in term %d.", g.Term())
 	case g.SlanderNationality == noNationality && cp.Chips[n] < 1:
-		return nil, nil, noNationality, sn.NewVError("You don't have a %s favor to use for the slander.", n)
+		return nil, nil, noNationality, g.verror(ErrInsufficientChips, "You don't have a %s favor to use for the slander.", n)
 	case g.SlanderNationality != noNationality && cp.Chips[n] < 2:
-		return nil, nil, noNationality, sn.NewVError("You don't have two %s favors to use for the second slander.", n)
+		return nil, nil, noNationality, g.verror(ErrInsufficientChips, "You don't have two %s favors to use for the second slander.", n)
 	case p == nil:
-		return nil, nil, noNationality, sn.NewVError("You must select a player to slander.")
+		return nil, nil, noNationality, g.verror(ErrNoPlayerSelected, "You must select a player to slander.")
 	case cp.Equal(p):
-		return nil, nil, noNationality, sn.NewVError("You can't slander yourself.")
+		return nil, nil, noNationality, g.verror(ErrSlanderSelf, "You can't slander yourself.")
 	case g.SlanderedPlayer() != nil && !g.SlanderedPlayer().Equal(p):
-		return nil, nil, noNationality, sn.NewVError("You attempted to slander %s, but you are in the process or slandering %s.", g.NameFor(p), g.NameFor(g.SlanderedPlayer()))
+		return nil, nil, noNationality, g.verror(ErrSlanderInProgress, "You attempted to slander %s, but you are in the process or slandering %s.", g.NameFor(p), g.NameFor(g.SlanderedPlayer()))
 	case cp.Slandered == 1 && !w.adjacent(g.CurrentWard()):
-		return nil, nil, noNationality, sn.NewVError("Ward %d is not adjacent to ward %d.", w.ID, g.CurrentWardID)
+		return nil, nil, noNationality, g.verror(ErrNotAdjacent, "Ward %d is not adjacent to ward %d.", w.ID, g.CurrentWardID)
 	case cp.Slandered == 1 && g.SlanderNationality != n:
-		return nil, nil, noNationality, sn.NewVError("You attempted to slander using %s favors, but you are in the process or slandering using %s favors.", n, g.SlanderNationality)
+		return nil, nil, noNationality, g.verror(ErrSlanderInProgress, "You attempted to slander using %s favors, but you are in the process or slandering using %s favors.", n, g.SlanderNationality)
 	case cp.Slandered >= 2:
-		return nil, nil, noNationality, sn.NewVError("You have already slandered twice this term.")
+		return nil, nil, noNationality, g.verror(ErrLimitReached, "You have already slandered twice this term.")
 	case cp.Slandered == 0 && !cp.CanSlanderIn(g.Term()):
-		return nil, nil, noNationality, sn.NewVError("You have already slandered this term.")
+		return nil, nil, noNationality, g.verror(ErrLimitReached, "You have already slandered this term.")
 	default:
 		return p, w, n, nil
 	}
@@ -0,0 +1,127 @@
+package tammany
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/datastore"
+	"github.com/SlothNinja/game"
+)
+
+const snapshotKind = "Snapshot"
+
+// Snapshot provides the state of a game at a phase boundary, taken once the action crossing the boundary was recorded.
+// Since the game log, recorded actions and draws only grow, the snapshot records their lengths rather than their contents.
+// Data holds the gzip compressed, encoded state, less its log, actions and draws.
+type Snapshot struct {
+	Key           *datastore.Key `datastore:"__key__"`
+	Actions       int
+	Draws         int `datastore:",noindex"`
+	LogLength     int
+	Turn          int              `datastore:",noindex"`
+	Phase         game.Phase       `datastore:",noindex"`
+	SubPhase      game.SubPhase    `datastore:",noindex"`
+	Round         int              `datastore:",noindex"`
+	CPUserIndices game.UserIndices `datastore:",noindex"`
+	WinnerIDS     game.UserIndices `datastore:",noindex"`
+	Status        game.Status      `datastore:",noindex"`
+	Data          []byte           `datastore:",noindex"`
+	CreatedAt     time.Time
+}
+
+// snapshotKey provides the key of the snapshot of the game having key gk once its log held logLength entries.
+// A later snapshot of the game having the same log length replaces the snapshot.
+func snapshotKey(gk *datastore.Key, logLength int) *datastore.Key {
+	return datastore.NameKey(snapshotKind, strconv.Itoa(logLength), gk)
+}
+
+// snapshot provides a snapshot of the game as it stands.
+func (g *Game) snapshot() (*Snapshot, error) {
+	s := *g.State
+	s.Log, s.Actions, s.Draws = nil, nil, nil
+
+	bs, err := encodeState(&s)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := compress(bs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{
+		Key:           snapshotKey(g.Key, len(g.Log)),
+		Actions:       len(g.Actions),
+		Draws:         len(g.Draws),
+		LogLength:     len(g.Log),
+		Turn:          g.Turn,
+		Phase:         g.Phase,
+		SubPhase:      g.SubPhase,
+		Round:         g.Round,
+		CPUserIndices: append(game.UserIndices(nil), g.CPUserIndices...),
+		WinnerIDS:     append(game.UserIndices(nil), g.WinnerIDS...),
+		Status:        g.Status,
+		Data:          data,
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// decodeState decodes the state held by the snapshot.
+func (snap *Snapshot) decodeState() (*State, error) {
+	bs, err := decompress(snap.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	s, _, err := decodeState(bs)
+	return s, err
+}
+
+// restoreHeader restores the header fields recorded by the snapshot.
+func (g *Game) restoreHeader(snap *Snapshot) {
+	g.Turn = snap.Turn
+	g.Phase = snap.Phase
+	g.SubPhase = snap.SubPhase
+	g.Round = snap.Round
+	g.CPUserIndices = snap.CPUserIndices
+	g.WinnerIDS = snap.WinnerIDS
+	g.Status = snap.Status
+}
+
+// compress gzip compresses bs.
+func compress(bs []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	zw := gzip.NewWriter(buf)
+	_, err := zw.Write(bs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress decompresses gzip compressed bs.
+func decompress(bs []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(bs))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	return ioutil.ReadAll(zr)
+}
+
+// bySnapshotLogLength implements sort.Interface for sorting snapshots by log length.
+type bySnapshotLogLength []*Snapshot
+
+func (ss bySnapshotLogLength) Len() int           { return len(ss) }
+func (ss bySnapshotLogLength) Swap(i, j int)      { ss[i], ss[j] = ss[j], ss[i] }
+func (ss bySnapshotLogLength) Less(i, j int) bool { return ss[i].LogLength < ss[j].LogLength }
@@ -0,0 +1,62 @@
+package tammany
+
+import (
+	"testing"
+
+	"github.com/SlothNinja/game"
+)
+
+func TestSnapshotOmitsLogAndActions(t *testing.T) {
+	g := newTestGame(t, 3)
+	g.Actions = []string{"P1: FINISH"}
+
+	snap, err := g.snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.LogLength != len(g.Log) || snap.Actions != 1 {
+		t.Errorf("got log length %d and %d actions, want %d and 1", snap.LogLength, snap.Actions, len(g.Log))
+	}
+
+	s, err := snap.decodeState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Log) != 0 || len(s.Actions) != 0 {
+		t.Errorf("snapshot holds %d log entries and %d actions, want none", len(s.Log), len(s.Actions))
+	}
+	if len(s.Playerers) != 3 || len(s.Wards) != len(g.Wards) {
+		t.Errorf("snapshot holds %d players and %d wards, want 3 and %d", len(s.Playerers), len(s.Wards), len(g.Wards))
+	}
+}
+
+func TestPutSnapshotsPrunesCompletedGame(t *testing.T) {
+	client, c := newTestClient(), testContext()
+	g := newTestGame(t, 3)
+
+	for i := 0; i < 2; i++ {
+		g.newCastleGardenEntry(g.CurrentPlayer())
+		g.takeSnapshot()
+	}
+	client.putSnapshots(c, g)
+
+	snaps, err := client.Store.Snapshots(c, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 3 || len(g.snapshots) != 0 {
+		t.Fatalf("got %d saved and %d unsaved snapshots, want 3 saved", len(snaps), len(g.snapshots))
+	}
+	first := snaps[0].LogLength
+
+	g.endGame()
+	client.putSnapshots(c, g)
+
+	snaps, err = client.Store.Snapshots(c, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 1 || snaps[0].LogLength != first || g.Status != game.Completed {
+		t.Errorf("got %d snapshots of the completed game, want only the first", len(snaps))
+	}
+}
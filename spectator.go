@@ -0,0 +1,132 @@
+package tammany
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/SlothNinja/color"
+	"github.com/SlothNinja/game"
+	"github.com/SlothNinja/user"
+	"github.com/gin-gonic/gin"
+)
+
+// maxSpectatorDelay limits the spectator delay of a game.
+const maxSpectatorDelay = 500
+
+// delayedFor reports whether the view of the game by the user lags the game.
+// Players and admins view the game as it stands, as do all users once the game ended.
+func (g *Game) delayedFor(cu *user.User) bool {
+	return g.SpectatorDelay > 0 && g.Status == game.Running && !g.HasUser(cu) && !cu.IsAdmin()
+}
+
+// spectatorView provides the game as viewed by the user: the game itself or, if the view by the user lags the game,
+// a replay of the game omitting the last SpectatorDelay actions.
+// Failing to replay the game is an error, lest the view reveal the actions it should omit.
+func (client *Client) spectatorView(c *gin.Context, g *Game, cu *user.User) (*Game, error) {
+	if !g.delayedFor(cu) {
+		return g, nil
+	}
+
+	n := len(g.Actions) - g.SpectatorDelay
+	if n < 0 {
+		n = 0
+	}
+
+	g2, err := client.Replay(c, g, n)
+	if err != nil {
+		return nil, fmt.Errorf("unable to provide the delayed view of game %d: %w", g.ID(), err)
+	}
+	g2.lang = g.lang
+	g2.SpectatorDelay = g.SpectatorDelay
+	return g2, nil
+}
+
+// spectate replaces the fetched game by the view of the game by the current user.
+func (client *Client) spectate(c *gin.Context) {
+	client.Log.Debugf(msgEnter)
+	defer client.Log.Debugf(msgExit)
+
+	g := gameFrom(c)
+	if g == nil {
+		return
+	}
+
+	cu, err := client.User.Current(c)
+	if err != nil {
+		client.Log.Debugf(err.Error())
+	}
+
+	g2, err := client.spectatorView(c, g, cu)
+	if err != nil {
+		client.Log.Errorf(err.Error())
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	color.WithMap(withGame(c, g2), g2.ColorMapFor(cu))
+}
+
+// setSpectatorDelay sets the number of actions by which the view of users not playing the game lags the game.
+// Only the creator of the game or an admin may set the spectator delay.
+func (client *Client) setSpectatorDelay(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		cu, err := client.User.Current(c)
+		if err != nil || cu == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You must be logged in to set the spectator delay."})
+			return
+		}
+
+		id, err := getID(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Load the saved game, lest the creator's cached, unfinished turn be saved with the delay.
+		g := New(c, id)
+		err = client.dsGet(c, g)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		if cu.ID() != g.CreatorID && !cu.IsAdmin() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the creator of the game or an admin may set the spectator delay."})
+			return
+		}
+
+		if g.State == nil || g.Status != game.Running {
+			c.JSON(http.StatusConflict, gin.H{"error": "The spectator delay may be set only for running games."})
+			return
+		}
+
+		obj := struct {
+			Delay *int `form:"delay" json:"delay" binding:"required"`
+		}{}
+
+		err = c.ShouldBind(&obj)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if *obj.Delay < 0 || *obj.Delay > maxSpectatorDelay {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("The spectator delay must be between 0 and %d actions.", maxSpectatorDelay)})
+			return
+		}
+
+		g.SpectatorDelay = *obj.Delay
+		err = g.encode(c)
+		if err == nil {
+			err = client.Store.Put(c, g, nil, nil)
+		}
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"delay": g.SpectatorDelay})
+	}
+}
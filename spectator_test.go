@@ -0,0 +1,112 @@
+package tammany
+
+import (
+	"testing"
+
+	"github.com/SlothNinja/game"
+	"github.com/SlothNinja/user"
+)
+
+func TestDelayedFor(t *testing.T) {
+	g := newTestGame(t, 3)
+	g.SpectatorDelay = 3
+
+	player, spectator := user.New(g.UserIDS[1]), user.New(50)
+	tests := []struct {
+		name    string
+		cu      *user.User
+		delay   int
+		status  game.Status
+		delayed bool
+	}{
+		{"player", player, 3, game.Running, false},
+		{"admin", testAdmin(), 3, game.Running, false},
+		{"spectator", spectator, 3, game.Running, true},
+		{"anonymous", nil, 3, game.Running, true},
+		{"no delay", spectator, 0, game.Running, false},
+		{"completed", spectator, 3, game.Completed, false},
+	}
+	for _, test := range tests {
+		g.SpectatorDelay, g.Status = test.delay, test.status
+		if got := g.delayedFor(test.cu); got != test.delayed {
+			t.Errorf("%s: got delayed %v, want %v", test.name, got, test.delayed)
+		}
+	}
+}
+
+func TestSpectatorView(t *testing.T) {
+	client, c := newTestClient(), testContext()
+	g := newTestGame(t, 3)
+	g.SpectatorDelay = 3
+
+	views := []string{gameView(t, g)}
+	for turn := 0; turn < 4; turn++ {
+		for _, m := range turnMoves(g) {
+			err := client.applyMove(c, g, m)
+			if err != nil {
+				t.Fatalf("turn %d: %q: %v", turn, m, err)
+			}
+			views = append(views, gameView(t, g))
+		}
+	}
+
+	g2, err := client.spectatorView(c, g, user.New(g.UserIDS[0]))
+	if err != nil || g2 != g {
+		t.Errorf("got view %p and error %v for a player, want the game %p itself", g2, err, g)
+	}
+
+	g2, err = client.spectatorView(c, g, user.New(50))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := len(g.Actions) - g.SpectatorDelay
+	if got := gameView(t, g2); got != views[n] {
+		t.Errorf("got spectator view\n%s\nwant the game after %d actions\n%s", got, n, views[n])
+	}
+	if g2.SpectatorDelay != g.SpectatorDelay {
+		t.Errorf("got spectator delay %d of view, want %d", g2.SpectatorDelay, g.SpectatorDelay)
+	}
+
+	// A delay exceeding the actions of the game shows the game as started.
+	g.SpectatorDelay = maxSpectatorDelay
+	g2, err = client.spectatorView(c, g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2.SpectatorDelay = 3
+	if got := gameView(t, g2); got != views[0] {
+		t.Errorf("got spectator view\n%s\nwant the game as started\n%s", got, views[0])
+	}
+}
+
+func TestSpectatorExportOmitsDelayedActions(t *testing.T) {
+	client, c := newTestClient(), testContext()
+	g := newTestGame(t, 3)
+	g.SpectatorDelay = 3
+	playTurns(t, client, g, 4)
+
+	// As loaded, the game holds its saved state.
+	var err error
+	g.SavedState, err = encodeState(g.State)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g2, err := client.spectatorView(c, g, user.New(50))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := g2.archive(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, _, err := decodeState(a.State)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(s.Actions), len(g.Actions)-g.SpectatorDelay; got != want {
+		t.Errorf("got %d actions in the archive of the delayed view, want %d", got, want)
+	}
+}
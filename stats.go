@@ -0,0 +1,238 @@
+package tammany
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/SlothNinja/game"
+	"github.com/SlothNinja/sn"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	uidParam = "uid"
+	mayorVP  = 3
+)
+
+// VPBreakdown provides the victory points scored by a player by source.
+type VPBreakdown struct {
+	Wards          int `json:"wards"`
+	Ward14Bonus    int `json:"ward14Bonus"`
+	Mayor          int `json:"mayor"`
+	ChipMajorities int `json:"chipMajorities"`
+	SlanderChips   int `json:"slanderChips"`
+}
+
+// Total provides the total victory points of the breakdown.
+func (vp VPBreakdown) Total() int {
+	return vp.Wards + vp.Ward14Bonus + vp.Mayor + vp.ChipMajorities + vp.SlanderChips
+}
+
+func (vp *VPBreakdown) add(vp2 VPBreakdown) {
+	vp.Wards += vp2.Wards
+	vp.Ward14Bonus += vp2.Ward14Bonus
+	vp.Mayor += vp2.Mayor
+	vp.ChipMajorities += vp2.ChipMajorities
+	vp.SlanderChips += vp2.SlanderChips
+}
+
+// playerGameStats provides the statistics of a player in a single game.
+type playerGameStats struct {
+	Terms             int
+	WardsWon          int
+	TimesMayor        int
+	SlandersPerformed int
+	SlandersReceived  int
+	LockupsUsed       int
+	FavorChipsAtEnd   int
+	VP                VPBreakdown
+}
+
+// playerGameStats compiles the statistics of each player of the game from the game log, keyed by player id.
+func (g *Game) playerGameStats() map[int]*playerGameStats {
+	stats := make(map[int]*playerGameStats, len(g.Players()))
+	for _, p := range g.Players() {
+		stats[p.ID()] = &playerGameStats{FavorChipsAtEnd: p.Chips.Count()}
+	}
+
+	for _, sb := range g.ScoreBreakdowns() {
+		s := stats[sb.PlayerID]
+		s.Terms = len(sb.Terms)
+		s.VP = sb.VP
+		for _, ts := range sb.Terms {
+			s.WardsWon += len(ts.Wards)
+			if ts.Mayor {
+				s.TimesMayor++
+			}
+		}
+	}
+
+	statsFor := func(pid int) *playerGameStats {
+		if s, ok := stats[pid]; ok {
+			return s
+		}
+		return new(playerGameStats)
+	}
+
+	for _, ev := range g.Log.Events() {
+		switch ev.Kind {
+		case "firstSlander", "secondSlander":
+			statsFor(ev.PlayerID).SlandersPerformed++
+			statsFor(ev.OtherPlayerID).SlandersReceived++
+		case "placedLockUpMarker":
+			statsFor(ev.PlayerID).LockupsUsed++
+		}
+	}
+	return stats
+}
+
+// PlayerStats provides the career statistics of a user across completed Tammany Hall games.
+type PlayerStats struct {
+	UserID             int64       `json:"userId"`
+	Name               string      `json:"name"`
+	Games              int         `json:"games"`
+	Wins               int         `json:"wins"`
+	Terms              int         `json:"terms"`
+	WardsWon           int         `json:"wardsWon"`
+	WardsPerTerm       float64     `json:"wardsPerTerm"`
+	TimesMayor         int         `json:"timesMayor"`
+	SlandersPerformed  int         `json:"slandersPerformed"`
+	SlandersReceived   int         `json:"slandersReceived"`
+	LockupsUsed        int         `json:"lockupsUsed"`
+	FavorChipsAtEnd    int         `json:"favorChipsAtEnd"`
+	AvgFavorChipsAtEnd float64     `json:"avgFavorChipsAtEnd"`
+	VP                 VPBreakdown `json:"vp"`
+}
+
+func (ps *PlayerStats) add(g *Game, pid int, s *playerGameStats) {
+	ps.Games++
+	for _, wid := range g.WinnerIDS {
+		if wid == pid {
+			ps.Wins++
+			break
+		}
+	}
+	ps.Terms += s.Terms
+	ps.WardsWon += s.WardsWon
+	ps.TimesMayor += s.TimesMayor
+	ps.SlandersPerformed += s.SlandersPerformed
+	ps.SlandersReceived += s.SlandersReceived
+	ps.LockupsUsed += s.LockupsUsed
+	ps.FavorChipsAtEnd += s.FavorChipsAtEnd
+	ps.VP.add(s.VP)
+}
+
+func (ps *PlayerStats) average() {
+	if ps.Terms > 0 {
+		ps.WardsPerTerm = float64(ps.WardsWon) / float64(ps.Terms)
+	}
+	if ps.Games > 0 {
+		ps.AvgFavorChipsAtEnd = float64(ps.FavorChipsAtEnd) / float64(ps.Games)
+	}
+}
+
+func statsKey(uid int64) string {
+	return fmt.Sprintf("tammany-stats-%d", uid)
+}
+
+// playerStats aggregates the statistics of the user with id uid across completed games.
+func (client *Client) playerStats(c *gin.Context, uid int64) (*PlayerStats, error) {
+	client.Log.Debugf(msgEnter)
+	defer client.Log.Debugf(msgExit)
+
+	if item, found := client.Cache.Get(statsKey(uid)); found {
+		if ps, ok := item.(*PlayerStats); ok {
+			return ps, nil
+		}
+	}
+
+	gs, err := client.Store.ListByUser(c, game.Completed, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := &PlayerStats{UserID: uid}
+	for _, g := range gs {
+		s, _, err := decodeState(g.SavedState)
+		if err != nil {
+			client.Log.Warningf("unable to decode state of game %d: %v", g.ID(), err)
+			continue
+		}
+		g.State = s
+
+		pid := g.IndexFor(uid)
+		if ps.Name == "" {
+			ps.Name = g.NameByUID(uid)
+		}
+		if pgs, ok := g.playerGameStats()[pid]; ok {
+			ps.add(g, pid, pgs)
+		}
+	}
+	ps.average()
+
+	client.Cache.SetDefault(statsKey(uid), ps)
+	return ps, nil
+}
+
+func getUID(c *gin.Context) (int64, error) {
+	uid, err := strconv.ParseInt(c.Param(uidParam), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidID
+	}
+	return uid, nil
+}
+
+func (client *Client) showStats(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		uid, err := getUID(c)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.Redirect(http.StatusSeeOther, homePath)
+			return
+		}
+
+		ps, err := client.playerStats(c, uid)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.Redirect(http.StatusSeeOther, homePath)
+			return
+		}
+
+		cu, err := client.User.Current(c)
+		if err != nil {
+			client.Log.Debugf(err.Error())
+		}
+
+		c.HTML(http.StatusOK, prefix+"/stats", gin.H{
+			"Context":   c,
+			"VersionID": sn.VersionID(),
+			"CUser":     cu,
+			"Stats":     ps,
+		})
+	}
+}
+
+func (client *Client) jsonStats(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		uid, err := getUID(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ps, err := client.playerStats(c, uid)
+		if err != nil {
+			client.Log.Errorf(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, ps)
+	}
+}
@@ -0,0 +1,246 @@
+package tammany
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/datastore"
+	"github.com/SlothNinja/game"
+	"github.com/SlothNinja/mlog"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrGameChanged  = errors.New("Game state changed unexpectantly.  Try again.")
+	ErrGameNotFound = errors.New("game not found")
+)
+
+// GameStore provides persistence of Tammany Hall games.
+type GameStore interface {
+	// Get loads the header and saved state of the game identified by g.Key.
+	Get(*gin.Context, *Game) error
+
+	// Put saves the game, provided the stored game has not been updated since the game was loaded.
+	// Entities es are saved alongside the game using keys ks.
+	Put(*gin.Context, *Game, []*datastore.Key, []interface{}) error
+
+	// Create allocates an id for the game and saves it together with its message log, holding the messages.
+	Create(*gin.Context, *Game, []*mlog.Message) error
+
+	// GetMLog loads the message log of the game having the id.
+	GetMLog(*gin.Context, int64) (*mlog.MLog, error)
+
+	// PutMLog saves the message log.
+	PutMLog(*gin.Context, *mlog.MLog) error
+
+	// ListByStatus returns the games having the status, most recently updated first.
+	ListByStatus(*gin.Context, game.Status) (Games, error)
+
+	// ListByUser returns the games having the status in which the user plays, most recently updated first.
+	ListByUser(*gin.Context, game.Status, int64) (Games, error)
+
+	// PutSnapshot saves a snapshot of the game, replacing any snapshot having the same log length.
+	PutSnapshot(*gin.Context, *Game, *Snapshot) error
+
+	// Snapshots returns the snapshots of the game, ordered by log length.
+	Snapshots(*gin.Context, *Game) ([]*Snapshot, error)
+
+	// DeleteSnapshots deletes the snapshots of the game having a log length greater than the given log length.
+	DeleteSnapshots(*gin.Context, *Game, int) error
+}
+
+// storeEnv names the environment variable selecting the stores of a client.
+// Its value is "memory", keeping games, preferences, and follows in memory, or "file:" followed by a directory,
+// keeping games in files of the directory, and preferences and follows in memory.
+// Otherwise, the client keeps all in the datastore.
+const storeEnv = "TAMMANY_STORE"
+
+// withStoresFrom sets the stores of the client as selected by the value of storeEnv.
+func (client *Client) withStoresFrom(selected string) *Client {
+	switch {
+	case selected == "memory":
+		client.Store = NewMemoryStore()
+	case strings.HasPrefix(selected, "file:"):
+		s, err := NewFileStore(strings.TrimPrefix(selected, "file:"))
+		if err != nil {
+			client.Log.Panicf("unable to open file store: %v", err)
+		}
+		client.Store = s
+	default:
+		client.Store = newDSStore(client.DS, client.MLog)
+		client.Prefs = newDSPrefsStore(client.DS)
+		client.Follows = newDSFollowStore(client.DS)
+		return client
+	}
+
+	client.Log.Warningf("%s=%s: games are not kept in the datastore", storeEnv, selected)
+	client.Prefs = NewMemoryPrefsStore()
+	client.Follows = NewMemoryFollowStore()
+	return client
+}
+
+// WithStore sets the store used to persist games.
+func (client *Client) WithStore(s GameStore) *Client {
+	client.Store = s
+	return client
+}
+
+type dsStore struct {
+	*datastore.Client
+	mlog *mlog.Client
+}
+
+func newDSStore(dsClient *datastore.Client, mlogClient *mlog.Client) *dsStore {
+	return &dsStore{Client: dsClient, mlog: mlogClient}
+}
+
+func (s *dsStore) Get(c *gin.Context, g *Game) error {
+	return s.Client.Get(c, g.Key, g.Header)
+}
+
+func (s *dsStore) Put(c *gin.Context, g *Game, ks []*datastore.Key, es []interface{}) error {
+	_, err := s.RunInTransaction(c, func(tx *datastore.Transaction) error {
+		oldG := New(c, g.ID())
+		err := tx.Get(oldG.Key, oldG.Header)
+		if err != nil {
+			return err
+		}
+
+		if !oldG.UpdatedAt.Equal(g.UpdatedAt) {
+			return ErrGameChanged
+		}
+
+		ks = append(ks, g.Key)
+		es = append(es, g.Header)
+
+		_, err = tx.PutMulti(ks, es)
+		return err
+	})
+	return err
+}
+
+func (s *dsStore) Create(c *gin.Context, g *Game, ms []*mlog.Message) error {
+	ks, err := s.AllocateIDs(c, []*datastore.Key{g.Key})
+	if err != nil {
+		return err
+	}
+
+	k := ks[0]
+
+	_, err = s.RunInTransaction(c, func(tx *datastore.Transaction) error {
+		m := mlog.New(k.ID)
+		m.Messages = ms
+		ks := []*datastore.Key{m.Key, k}
+		es := []interface{}{m, g.Header}
+		_, err := tx.PutMulti(ks, es)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	g.Key = k
+	return nil
+}
+
+func (s *dsStore) GetMLog(c *gin.Context, id int64) (*mlog.MLog, error) {
+	return s.mlog.Get(c, id)
+}
+
+func (s *dsStore) PutMLog(c *gin.Context, ml *mlog.MLog) error {
+	_, err := s.mlog.Put(c, ml.Key.ID, ml)
+	return err
+}
+
+func (s *dsStore) ListByStatus(c *gin.Context, status game.Status) (Games, error) {
+	q := datastore.NewQuery(kind).
+		Ancestor(pk(c)).
+		Filter("Status=", int(status)).
+		Order("-UpdatedAt").
+		KeysOnly()
+
+	return s.list(c, q)
+}
+
+func (s *dsStore) ListByUser(c *gin.Context, status game.Status, uid int64) (Games, error) {
+	q := datastore.NewQuery(kind).
+		Ancestor(pk(c)).
+		Filter("Status=", int(status)).
+		Filter("UserIDS=", uid).
+		Order("-UpdatedAt").
+		KeysOnly()
+
+	return s.list(c, q)
+}
+
+// list returns the games identified by keys-only query q.
+func (s *dsStore) list(c *gin.Context, q *datastore.Query) (Games, error) {
+	ks, err := s.GetAll(c, q, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	gs := make(Games, len(ks))
+	hs := make([]*game.Header, len(ks))
+	for i, k := range ks {
+		gs[i] = New(c, k.ID)
+		hs[i] = gs[i].Header
+	}
+
+	err = s.GetMulti(c, ks, hs)
+	if err != nil {
+		return nil, err
+	}
+	return gs, nil
+}
+
+func (s *dsStore) PutSnapshot(c *gin.Context, g *Game, snap *Snapshot) error {
+	_, err := s.Client.Put(c, snap.Key, snap)
+	return err
+}
+
+func (s *dsStore) Snapshots(c *gin.Context, g *Game) ([]*Snapshot, error) {
+	q := datastore.NewQuery(snapshotKind).Ancestor(g.Key)
+
+	var snaps []*Snapshot
+	_, err := s.GetAll(c, q, &snaps)
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(bySnapshotLogLength(snaps))
+	return snaps, nil
+}
+
+func (s *dsStore) DeleteSnapshots(c *gin.Context, g *Game, logLength int) error {
+	q := datastore.NewQuery(snapshotKind).
+		Ancestor(g.Key).
+		Filter("LogLength>", logLength).
+		KeysOnly()
+
+	ks, err := s.GetAll(c, q, nil)
+	if err != nil {
+		return err
+	}
+	return s.DeleteMulti(c, ks)
+}
+
+// hasUser reports whether the user with id uid plays in game g.
+func hasUser(g *Game, uid int64) bool {
+	for _, id := range g.UserIDS {
+		if id == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// touch updates the timestamps of header h in the manner of datastore saves of game headers.
+func touch(h *game.Header) {
+	t := time.Now()
+	if h.CreatedAt.IsZero() {
+		h.CreatedAt = t
+	}
+	h.UpdatedAt = t
+}
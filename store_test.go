@@ -0,0 +1,89 @@
+package tammany
+
+import (
+	"testing"
+
+	"github.com/SlothNinja/mlog"
+	"github.com/SlothNinja/user"
+)
+
+func testStores(t *testing.T) map[string]GameStore {
+	fs, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]GameStore{"memory": NewMemoryStore(), "file": fs}
+}
+
+func TestStoreCreateSavesMLog(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			c := testContext()
+			g := New(c, 0)
+			g.Title = "test"
+
+			err := s.Create(c, g, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ml, err := s.GetMLog(c, g.ID())
+			if err != nil {
+				t.Fatalf("message log of created game: %v", err)
+			}
+			if len(ml.Messages) != 0 {
+				t.Errorf("created game has %d messages, want 0", len(ml.Messages))
+			}
+
+			u := user.New(7)
+			u.Name = "tester"
+			ml.AddMessage(u, "hello")
+			err = s.PutMLog(c, ml)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ml, err = s.GetMLog(c, g.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ml.Messages) != 1 || ml.Messages[0].Text != "hello" || ml.Messages[0].CreatorName != "tester" {
+				t.Errorf("got messages %+v, want the message of tester", ml.Messages)
+			}
+		})
+	}
+}
+
+func TestStoreGetMLogOfUnknownGame(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.GetMLog(testContext(), 42)
+			if err != mlog.ErrNotFound {
+				t.Errorf("got error %v, want %v", err, mlog.ErrNotFound)
+			}
+		})
+	}
+}
+
+func TestStoreCreateSavesMessages(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			c := testContext()
+			g := New(c, 0)
+			ms := []*mlog.Message{{Text: "imported", CreatorName: "tester"}}
+
+			err := s.Create(c, g, ms)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ml, err := s.GetMLog(c, g.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ml.Messages) != 1 || ml.Messages[0].Text != "imported" {
+				t.Errorf("got messages %+v, want the imported message", ml.Messages)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package tammany
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SlothNinja/user"
+	"github.com/gin-gonic/gin"
+)
+
+// Dimensions of the board, in the coordinates of wardCoords and officeCoords.
+const (
+	svgWidth  = 2000
+	svgHeight = 2100
+	bossSize  = 56
+	cubeSize  = 36
+)
+
+// Areas of the board lacking coordinates.
+var (
+	svgMayorCoords        = "1673,200,1938,200,1938,350,1673,350"
+	svgCastleGardenCoords = "1673,1130,1938,1130,1938,1420,1673,1420"
+)
+
+type svgPoint struct{ x, y int }
+
+// parseCoords parses the coordinates of an image map polygon.
+func parseCoords(coords string) []svgPoint {
+	var ps []svgPoint
+	ss := strings.Split(coords, ",")
+	for i := 0; i+1 < len(ss); i += 2 {
+		x, errX := strconv.Atoi(ss[i])
+		y, errY := strconv.Atoi(ss[i+1])
+		if errX == nil && errY == nil {
+			ps = append(ps, svgPoint{x, y})
+		}
+	}
+	return ps
+}
+
+// center provides the average of the vertices of a polygon.
+func center(ps []svgPoint) svgPoint {
+	if len(ps) == 0 {
+		return svgPoint{}
+	}
+	var c svgPoint
+	for _, p := range ps {
+		c.x += p.x
+		c.y += p.y
+	}
+	return svgPoint{c.x / len(ps), c.y / len(ps)}
+}
+
+func svgPoints(coords string) string {
+	return strings.Replace(coords, ",", " ", -1)
+}
+
+func svgText(s string) string {
+	return template.HTMLEscapeString(s)
+}
+
+// SVGBoard provides an SVG rendering of the board, as rendered by WriteSVGBoard.
+func (g *Game) SVGBoard(cu *user.User, imageBase string) string {
+	buf := new(bytes.Buffer)
+	g.WriteSVGBoard(buf, cu, imageBase)
+	return buf.String()
+}
+
+// WriteSVGBoard writes an SVG rendering of the board to w, drawing players in their colors as seen by cu, who may be nil.
+// Images are linked relative to imageBase, which should be absolute where the SVG is viewed apart from the site.
+// Pieces are drawn over shapes of their color, so the board remains legible where images are not loaded.
+func (g *Game) WriteSVGBoard(w io.Writer, cu *user.User, imageBase string) error {
+	ew := &errWriter{w: w}
+	imageBase = strings.TrimRight(imageBase, "/")
+
+	fmt.Fprintf(ew, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 %d %d" width="%d" height="%d" font-family="Georgia, serif">`+"\n",
+		svgWidth, svgHeight, svgWidth/2, svgHeight/2)
+	fmt.Fprintf(ew, `<rect width="%d" height="%d" fill="#f4ecd8"/>`+"\n", svgWidth, svgHeight)
+	fmt.Fprintf(ew, `<text x="40" y="80" font-size="56">Tammany Hall #%d: %s</text>`+"\n", g.ID(), svgText(g.Title))
+	fmt.Fprintf(ew, `<text x="40" y="150" font-size="44">Year %d | %s</text>`+"\n", g.Year(), svgText(g.T(phaseNames[g.Phase])))
+
+	active := make(map[wardID]bool)
+	for _, wd := range g.ActiveWards() {
+		active[wd.ID] = true
+	}
+	for _, wd := range g.Wards {
+		g.writeSVGWard(ew, wd, active[wd.ID], cu, imageBase)
+	}
+
+	g.writeSVGOffice(ew, mayor, svgMayorCoords, cu)
+	for _, o := range g.AssignableOffices() {
+		if coords, ok := officeCoords[o.IDString()]; ok {
+			g.writeSVGOffice(ew, o, coords, cu)
+		}
+	}
+	g.writeSVGCastleGarden(ew, imageBase)
+
+	fmt.Fprintln(ew, `</svg>`)
+	return ew.err
+}
+
+func (g *Game) writeSVGWard(w io.Writer, wd *Ward, active bool, cu *user.User, imageBase string) {
+	fill, stroke, width := "#fffaf0", "#5a4a32", 3
+	switch {
+	case !active:
+		fill = "#cfc8b8"
+	case wd.Resolved:
+		fill = "#e6dcc0"
+	}
+	if wd.ID == g.CurrentWardID {
+		stroke, width = "#b03a2e", 10
+	}
+
+	coords := wardCoords[wd.ID]
+	c := center(parseCoords(coords))
+	fmt.Fprintf(w, `<g id="ward-%d">`+"\n", wd.ID)
+	fmt.Fprintf(w, `<polygon points="%s" fill="%s" stroke="%s" stroke-width="%d"/>`+"\n", svgPoints(coords), fill, stroke, width)
+	fmt.Fprintf(w, `<text x="%d" y="%d" font-size="40" text-anchor="middle" fill="#5a4a32">%d</text>`+"\n", c.x, c.y-bossSize-10, wd.ID)
+
+	// Bosses above the center of the ward, and immigrants below it, each followed by its count.
+	var bosses []*Player
+	for _, p := range g.Players() {
+		if wd.BossesFor(p) > 0 {
+			bosses = append(bosses, p)
+		}
+	}
+	x := c.x - len(bosses)*(bossSize+30)/2
+	for _, p := range bosses {
+		color := g.Color(p, cu).String()
+		fmt.Fprintf(w, `<circle cx="%d" cy="%d" r="%d" fill="%s" stroke="#000"/>`+"\n", x+bossSize/2, c.y-bossSize/2, bossSize/2-4, color)
+		fmt.Fprintf(w, `<image x="%d" y="%d" width="%d" height="%d" xlink:href="%s"/>`+"\n", x, c.y-bossSize, bossSize, bossSize, imageBase+g.bossImagePath(p, cu))
+		fmt.Fprintf(w, `<text x="%d" y="%d" font-size="32">%d</text>`+"\n", x+bossSize+2, c.y-8, wd.BossesFor(p))
+		x += bossSize + 30
+	}
+
+	var ns []nationality
+	for _, n := range g.Nationalities() {
+		if wd.Immigrants[n] > 0 {
+			ns = append(ns, n)
+		}
+	}
+	x = c.x - len(ns)*(cubeSize+28)/2
+	for _, n := range ns {
+		writeSVGCube(w, n, x, c.y+10, imageBase)
+		fmt.Fprintf(w, `<text x="%d" y="%d" font-size="28">%d</text>`+"\n", x+cubeSize+2, c.y+10+cubeSize-6, wd.Immigrants[n])
+		x += cubeSize + 28
+	}
+
+	if wd.LockedUp {
+		fmt.Fprintf(w, `<rect x="%d" y="%d" width="150" height="44" rx="6" fill="#222"/>`+"\n", c.x-75, c.y+cubeSize+20)
+		fmt.Fprintf(w, `<text x="%d" y="%d" font-size="26" text-anchor="middle" fill="#fff">%s</text>`+"\n", c.x, c.y+cubeSize+51, svgText(g.T("LOCKED UP")))
+	}
+	fmt.Fprintln(w, `</g>`)
+}
+
+func writeSVGCube(w io.Writer, n nationality, x, y int, imageBase string) {
+	fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" fill="#bbb" stroke="#000"/>`+"\n", x, y, cubeSize, cubeSize)
+	fmt.Fprintf(w, `<text x="%d" y="%d" font-size="18" text-anchor="middle">%s</text>`+"\n", x+cubeSize/2, y+cubeSize-11, svgText(n.Abbr()[:1]))
+	fmt.Fprintf(w, `<image x="%d" y="%d" width="%d" height="%d" xlink:href="%s"/>`+"\n", x, y, cubeSize, cubeSize, imageBase+n.CubeImage())
+}
+
+func (g *Game) writeSVGOffice(w io.Writer, o office, coords string, cu *user.User) {
+	ps := parseCoords(coords)
+	if len(ps) == 0 {
+		return
+	}
+	fmt.Fprintf(w, `<g id="%s">`+"\n", o.IDString())
+	fmt.Fprintf(w, `<polygon points="%s" fill="#fffaf0" stroke="#5a4a32" stroke-width="3"/>`+"\n", svgPoints(coords))
+	fmt.Fprintf(w, `<text x="%d" y="%d" font-size="30">%s</text>`+"\n", ps[0].x+14, ps[0].y+44, svgText(g.T(o.String())))
+	if p := g.PlayerByOffice(o); p != nil {
+		fmt.Fprintf(w, `<circle cx="%d" cy="%d" r="16" fill="%s" stroke="#000"/>`+"\n", ps[0].x+30, ps[0].y+94, g.Color(p, cu))
+		fmt.Fprintf(w, `<text x="%d" y="%d" font-size="28">%s</text>`+"\n", ps[0].x+56, ps[0].y+104, svgText(g.NameFor(p)))
+	}
+	fmt.Fprintln(w, `</g>`)
+}
+
+func (g *Game) writeSVGCastleGarden(w io.Writer, imageBase string) {
+	ps := parseCoords(svgCastleGardenCoords)
+	fmt.Fprintln(w, `<g id="castle-garden">`)
+	fmt.Fprintf(w, `<polygon points="%s" fill="#fffaf0" stroke="#5a4a32" stroke-width="3"/>`+"\n", svgPoints(svgCastleGardenCoords))
+	fmt.Fprintf(w, `<text x="%d" y="%d" font-size="30">%s</text>`+"\n", ps[0].x+14, ps[0].y+44, svgText(g.T("Castle Garden")))
+	for i, n := range g.Nationalities() {
+		y := ps[0].y + 70 + i*(cubeSize+18)
+		writeSVGCube(w, n, ps[0].x+24, y, imageBase)
+		fmt.Fprintf(w, `<text x="%d" y="%d" font-size="28">%d</text>`+"\n", ps[0].x+24+cubeSize+12, y+cubeSize-6, g.CastleGarden[n])
+	}
+	fmt.Fprintln(w, `</g>`)
+}
+
+// svgBoard provides an SVG rendering of the board.
+// Given the actions query parameter, the board is rendered as it stood after that many recorded actions.
+func (client *Client) svgBoard(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		g := gameFrom(c)
+		if g == nil {
+			c.String(http.StatusNotFound, ErrGameNotFound.Error())
+			return
+		}
+
+		if s := c.Query("actions"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				c.String(http.StatusBadRequest, err.Error())
+				return
+			}
+
+			g, err = client.Replay(c, g, n)
+			if err != nil {
+				c.String(http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		cu, err := client.User.Current(c)
+		if err != nil {
+			client.Log.Debugf(err.Error())
+		}
+
+		c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", []byte(g.SVGBoard(cu, imageBase(c))))
+	}
+}
+
+// imageBase provides the scheme and host of the request, relative to which images are linked from outside the site.
+func imageBase(c *gin.Context) string {
+	if c == nil || c.Request == nil {
+		return ""
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -0,0 +1,192 @@
+package tammany
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/SlothNinja/user"
+	"github.com/gin-gonic/gin"
+)
+
+// TextBoard provides a plain text rendering of the board, as rendered by WriteTextBoard.
+func (g *Game) TextBoard(cu *user.User) string {
+	buf := new(bytes.Buffer)
+	g.WriteTextBoard(buf, cu)
+	return buf.String()
+}
+
+// WriteTextBoard writes a plain text rendering of the board to w: the active wards, the Castle Garden, the bag,
+// the offices, and the players. Players are named by color, as seen by cu, who may be nil.
+func (g *Game) WriteTextBoard(w io.Writer, cu *user.User) error {
+	ew := &errWriter{w: w}
+
+	fmt.Fprintf(ew, "Tammany Hall #%d: %s\n", g.ID(), g.Title)
+	fmt.Fprintln(ew, g.T("Year %d | %s | %s", g.Year(), g.T(phaseNames[g.Phase]), g.T(g.Status.String())))
+	if ps := g.CurrentPlayers(); len(ps) > 0 {
+		names := make([]string, len(ps))
+		for i, p := range ps {
+			names[i] = fmt.Sprintf("%s (%s)", g.NameFor(p), g.Color(p, cu))
+		}
+		fmt.Fprintln(ew, g.T("Current: %s", strings.Join(names, ", ")))
+	}
+	if g.ImmigrantInTransit != noNationality {
+		fmt.Fprintln(ew, g.T("Moving: %s immigrant", g.ImmigrantInTransit))
+	}
+	fmt.Fprintln(ew)
+
+	g.writeTextWards(ew, cu)
+	fmt.Fprintln(ew)
+
+	tw := tabwriter.NewWriter(ew, 0, 4, 1, ' ', 0)
+	fmt.Fprintf(tw, "%s:\t%s\n", g.T("Castle Garden"), g.textCounts(g.CastleGarden))
+	fmt.Fprintf(tw, "%s:\t%s\n", g.T("Bag"), g.textCounts(g.Bag))
+	tw.Flush()
+	fmt.Fprintln(ew)
+
+	var held []string
+	for _, o := range g.AssignableOffices() {
+		if p := g.PlayerByOffice(o); p != nil {
+			held = append(held, fmt.Sprintf("%s: %s", g.T(o.String()), g.NameFor(p)))
+		}
+	}
+	if len(held) > 0 {
+		fmt.Fprintf(ew, "%s\n\n", g.T("Offices: %s", strings.Join(held, ", ")))
+	}
+
+	g.writeTextPlayers(ew, cu)
+	return ew.err
+}
+
+func (g *Game) writeTextWards(w io.Writer, cu *user.User) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "  %s\t", g.T("Ward"))
+	for _, n := range g.Nationalities() {
+		fmt.Fprintf(tw, "%s\t", g.T(n.String()))
+	}
+	fmt.Fprintf(tw, "%s\t\n", g.T("Bosses"))
+
+	for _, wd := range g.ActiveWards() {
+		mark := " "
+		switch {
+		case wd.ID == g.CurrentWardID:
+			mark = ">"
+		case wd.LockedUp:
+			mark = "L"
+		case wd.Resolved:
+			mark = "R"
+		}
+		fmt.Fprintf(tw, "%s %d\t", mark, wd.ID)
+		for _, n := range g.Nationalities() {
+			fmt.Fprintf(tw, "%s\t", textCount(wd.Immigrants[n]))
+		}
+
+		var bosses []string
+		for _, p := range g.Players() {
+			if count := wd.BossesFor(p); count > 0 {
+				bosses = append(bosses, fmt.Sprintf("%s:%d", g.Color(p, cu), count))
+			}
+		}
+		fmt.Fprintf(tw, "%s\t\n", strings.Join(bosses, " "))
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "  %s\n", g.T("(> current ward, L locked up, R resolved)"))
+}
+
+func (g *Game) writeTextPlayers(w io.Writer, cu *user.User) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\t%s\t\n",
+		g.T("Player"), g.T("Color"), g.T("Score"), g.T("Office"), g.T("Favor Chips"), g.T("Slander Chips"))
+
+	current := make(map[int]bool)
+	for _, p := range g.CurrentPlayers() {
+		current[p.ID()] = true
+	}
+
+	for _, p := range g.Players() {
+		mark := " "
+		if current[p.ID()] {
+			mark = "*"
+		}
+
+		o := "-"
+		if p.Office != noOffice {
+			o = g.T(p.Office.String())
+		}
+
+		var terms []int
+		for term, ok := range p.SlanderChips {
+			if ok {
+				terms = append(terms, term)
+			}
+		}
+		sort.Ints(terms)
+		slander := "-"
+		if len(terms) > 0 {
+			ss := make([]string, len(terms))
+			for i, term := range terms {
+				ss[i] = fmt.Sprintf("T%d", term)
+			}
+			slander = strings.Join(ss, " ")
+		}
+
+		fmt.Fprintf(tw, "%s %s\t%s\t%d\t%s\t%s\t%s\t\n",
+			mark, g.NameFor(p), g.Color(p, cu), p.Score, o, g.textCounts(Nationals(p.Chips)), slander)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "  %s\n", g.T("(* current player; slander chips by term)"))
+}
+
+func textCount(n int) string {
+	if n == 0 {
+		return "."
+	}
+	return fmt.Sprint(n)
+}
+
+func (g *Game) textCounts(ns Nationals) string {
+	ss := make([]string, len(nationalities()))
+	for i, n := range nationalities() {
+		ss[i] = g.T("%s %d", n, ns[n])
+	}
+	return strings.Join(ss, ", ")
+}
+
+// errWriter retains the first error of writes to w, ignoring later writes.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	var n int
+	n, ew.err = ew.w.Write(p)
+	return n, ew.err
+}
+
+// textBoard provides a plain text rendering of the board.
+func (client *Client) textBoard(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		g := gameFrom(c)
+		if g == nil {
+			c.String(http.StatusNotFound, ErrGameNotFound.Error())
+			return
+		}
+
+		cu, err := client.User.Current(c)
+		if err != nil {
+			client.Log.Debugf(err.Error())
+		}
+		c.String(http.StatusOK, g.TextBoard(cu))
+	}
+}
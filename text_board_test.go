@@ -0,0 +1,23 @@
+package tammany
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTextBoardInGerman(t *testing.T) {
+	g := newTestGame(t, 3)
+
+	var b bytes.Buffer
+	err := g.withLanguage("de").WriteTextBoard(&b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"Jahr 1 |", "Am Zug:", "Bezirk", "Bosse", "Beutel:", "Spieler", "Verleumdungschips"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("got board\n%s\nwant it to contain %q", b.String(), want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package tammany
+
+import (
+	"github.com/SlothNinja/game"
+	"github.com/gin-gonic/gin"
+)
+
+// maxSummaryEntries limits the entries of the log summarized by a turn notification.
+const maxSummaryEntries = 40
+
+// entriesSince provides the entries of the log following the last entry of the player,
+// or the entire log if the player has no entry.
+func (g *Game) entriesSince(p *Player) GameLog {
+	for i := len(g.Log) - 1; i >= 0; i-- {
+		if g.Log[i].Event().PlayerID == p.ID() {
+			return g.Log[i+1:]
+		}
+	}
+	return g.Log
+}
+
+// sendTurnNotifications notifies the players that it is their turn.
+// Each notification includes the entries of the log since the player's previous turn and the board as it now stands.
+func (client *Client) sendTurnNotifications(c *gin.Context, g *Game, ps ...game.Playerer) error {
+	var result error
+	for _, per := range ps {
+		p, ok := per.(*Player)
+		if !ok {
+			continue
+		}
+
+		err := client.notify(c, g, p, func(lang string) (*Notification, error) {
+			return g.turnNotificationFor(c, p, lang)
+		})
+		if err != nil {
+			client.Log.Warningf("unable to notify %s of turn in game %d: %v", g.NameFor(p), g.ID(), err)
+			result = err
+		}
+	}
+	return result
+}
+
+func (g *Game) turnNotificationFor(c *gin.Context, p *Player, lang string) (*Notification, error) {
+	g = g.withLanguage(lang)
+	u := p.User()
+	note := g.newNotification(c, turnNotice)
+
+	es := g.entriesSince(p)
+	omitted := 0
+	if len(es) > maxSummaryEntries {
+		omitted, es = len(es)-maxSummaryEntries, es[len(es)-maxSummaryEntries:]
+	}
+
+	entries := make([]string, len(es))
+	for i, e := range es {
+		entries[i] = e.Text(g)
+	}
+
+	err := renderNotification(note, lang, turnNotice, struct {
+		GameID  int64
+		Title   string
+		Link    string
+		Year    int
+		Phase   string
+		Entries []string
+		Omitted int
+		Board   string
+	}{g.ID(), g.Title, note.Link, g.Year(), g.T(phaseNames[g.Phase]), entries, omitted, g.TextBoard(u)})
+	if err != nil {
+		return nil, err
+	}
+
+	note.Board = g.SVGBoard(u, imageBase(c))
+	return note, nil
+}
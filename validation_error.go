@@ -0,0 +1,128 @@
+package tammany
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/SlothNinja/game"
+	"github.com/SlothNinja/sn"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCode identifies the rule violated by an action, independent of the language of its message.
+// Codes are stable, so clients may match on them.
+type ErrorCode string
+
+// Error provides the code, so that errors.Is(err, code) reports whether a ValidationError has the code.
+func (code ErrorCode) Error() string {
+	return string(code)
+}
+
+// Codes of validation errors.
+const (
+	ErrNotCurrentPlayer  ErrorCode = "not_current_player"
+	ErrWrongPhase        ErrorCode = "wrong_phase"
+	ErrWrongWard         ErrorCode = "wrong_ward"
+	ErrWardLocked        ErrorCode = "ward_locked"
+	ErrNotAdjacent       ErrorCode = "not_adjacent"
+	ErrNoWardSelected    ErrorCode = "no_ward_selected"
+	ErrNoPlayerSelected  ErrorCode = "no_player_selected"
+	ErrNoChipSelected    ErrorCode = "no_chip_selected"
+	ErrInsufficientChips ErrorCode = "insufficient_chips"
+	ErrNoImmigrant       ErrorCode = "no_immigrant"
+	ErrLastImmigrant     ErrorCode = "last_immigrant"
+	ErrAlreadyActed      ErrorCode = "already_acted"
+	ErrLimitReached      ErrorCode = "limit_reached"
+	ErrPlacingPieces     ErrorCode = "placing_pieces"
+	ErrActionIncomplete  ErrorCode = "action_incomplete"
+	ErrWrongOffice       ErrorCode = "wrong_office"
+	ErrMissingOfficer    ErrorCode = "missing_officer"
+	ErrOfficeAssigned    ErrorCode = "office_assigned"
+	ErrSlanderSelf       ErrorCode = "slander_self"
+	ErrSlanderInProgress ErrorCode = "slander_in_progress"
+	ErrInvalidAction     ErrorCode = "invalid_action"
+	ErrInvalidValue      ErrorCode = "invalid_value"
+	ErrInvalidMove       ErrorCode = "invalid_move"
+	ErrMissingUser       ErrorCode = "missing_user"
+	ErrNotCandidate      ErrorCode = "not_candidate"
+	ErrNotAdmin          ErrorCode = "not_admin"
+	ErrMissingReason     ErrorCode = "missing_reason"
+	ErrInvalidEntry      ErrorCode = "invalid_entry"
+	ErrNoSnapshot        ErrorCode = "no_snapshot"
+	ErrGameNotRunning    ErrorCode = "game_not_running"
+)
+
+// ValidationError provides a violation of the rules by an action, identified by Code.
+// Params provides the parameters of the message, such as ward ids and nationalities, in English.
+type ValidationError struct {
+	Code   ErrorCode
+	Params []string
+
+	msgid string
+	args  []interface{}
+	lang  string
+}
+
+// Error provides the message of the error in the language of the user attempting the action.
+func (e *ValidationError) Error() string {
+	return translate(e.lang, e.msgid, e.args...)
+}
+
+// Message provides the message of the error in the language.
+func (e *ValidationError) Message(lang string) string {
+	return translate(lang, e.msgid, e.args...)
+}
+
+// Is reports whether target is the code of the error.
+func (e *ValidationError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && code == e.Code
+}
+
+// newValidationError provides a validation error having the code, whose message is in the default language.
+func newValidationError(code ErrorCode, msgid string, args ...interface{}) *ValidationError {
+	params := make([]string, len(args))
+	for i, arg := range args {
+		if p, ok := arg.(game.Phase); ok {
+			arg = phaseNames[p]
+		}
+		params[i] = fmt.Sprint(arg)
+	}
+	return &ValidationError{Code: code, Params: params, msgid: msgid, args: args, lang: defaultLanguage}
+}
+
+// verror provides a validation error having the code, whose message is in the language of the game's viewer.
+func (g *Game) verror(code ErrorCode, msgid string, args ...interface{}) *ValidationError {
+	e := newValidationError(code, msgid, args...)
+	e.lang = g.language()
+	return e
+}
+
+// invalidMove provides a validation error of the notation or form values of a move, which are parsed apart from any game.
+func invalidMove(msgid string, args ...interface{}) *ValidationError {
+	return newValidationError(ErrInvalidMove, msgid, args...)
+}
+
+// validationErrorFrom provides the ValidationError of err, if any.
+func validationErrorFrom(err error) (*ValidationError, bool) {
+	var ve *ValidationError
+	ok := errors.As(err, &ve)
+	return ve, ok
+}
+
+// validationJSON provides the JSON rendering of a validation error: its message, code, and parameters.
+// Validation errors of the sn package have the code ErrInvalidMove.
+func validationJSON(err error) gin.H {
+	ve, ok := validationErrorFrom(err)
+	if !ok {
+		return gin.H{"error": err.Error(), "code": ErrInvalidMove, "params": []string{}}
+	}
+	return gin.H{"error": ve.Error(), "code": ve.Code, "params": ve.Params}
+}
+
+// isValidationError reports whether err results from an invalid action,
+// whether a ValidationError or a validation error of the sn package.
+func isValidationError(err error) bool {
+	_, ok := validationErrorFrom(err)
+	return ok || sn.IsVError(err)
+}
@@ -143,6 +143,21 @@ func (ns Nationals) draw() (n nationality) {
 	return
 }
 
+// drawFrom draws an immigrant at random from ns, recording the draw.
+// A replay of the game instead draws the immigrants drawn by the game, so long as ns holds them.
+func (g *Game) drawFrom(ns Nationals) nationality {
+	var n nationality
+	if len(g.redraws) > 0 && ns[g.redraws[0]] > 0 {
+		n, g.redraws = g.redraws[0], g.redraws[1:]
+		ns[n]--
+	} else {
+		g.redraws = nil
+		n = ns.draw()
+	}
+	g.Draws = append(g.Draws, n)
+	return n
+}
+
 func (ns Nationals) count() (cnt int) {
 	for _, v := range ns {
 		cnt += v
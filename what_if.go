@@ -0,0 +1,197 @@
+package tammany
+
+import (
+	"net/http"
+
+	"github.com/SlothNinja/user"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	outcomeWin  = "win"
+	outcomeTie  = "tie"
+	outcomeLose = "lose"
+)
+
+// WhatIf provides the possible outcomes of the election in the current ward for each legal bid of a player.
+type WhatIf struct {
+	WardID    int               `json:"wardId"`
+	PlayerID  int               `json:"playerId"`
+	Bosses    int               `json:"bosses"`
+	Opponents []*WhatIfOpponent `json:"opponents"`
+	Bids      []*BidOutcome     `json:"bids"`
+}
+
+// WhatIfOpponent provides the range of election counts an opponent may reach in the current ward.
+type WhatIfOpponent struct {
+	PlayerID     int    `json:"playerId"`
+	Name         string `json:"name"`
+	Bosses       int    `json:"bosses"`
+	MaxInfluence int    `json:"maxInfluence"`
+}
+
+// BidOutcome provides the outcomes of a bid against every combination of election counts of the opponents.
+// Best and Worst provide the outcome should the opponents play no chips and every playable chip, respectively.
+type BidOutcome struct {
+	Chips  map[string]int `json:"chips"`
+	Count  int            `json:"count"`
+	Wins   int            `json:"wins"`
+	Ties   int            `json:"ties"`
+	Losses int            `json:"losses"`
+	Best   string         `json:"best"`
+	Worst  string         `json:"worst"`
+}
+
+// WhatIf enumerates the outcomes of the election in the current ward for each legal bid of player p.
+func (g *Game) WhatIf(p *Player) *WhatIf {
+	w := g.CurrentWard()
+	wi := &WhatIf{WardID: int(w.ID), PlayerID: p.ID(), Bosses: w.BossesFor(p)}
+
+	for _, cd := range g.candidates() {
+		if cd.Equal(p) {
+			continue
+		}
+		wi.Opponents = append(wi.Opponents, &WhatIfOpponent{
+			PlayerID:     cd.ID(),
+			Name:         g.NameFor(cd),
+			Bosses:       w.BossesFor(cd),
+			MaxInfluence: cd.MaxInfluenceIn(w),
+		})
+	}
+
+	outcomes := make(map[int]*BidOutcome)
+	for _, cs := range g.legalBidsFor(p, w) {
+		// Bids having the same election count have the same outcomes.
+		count := wi.Bosses + cs.Count()
+		o, ok := outcomes[count]
+		if !ok {
+			o = wi.outcomesFor(count)
+			outcomes[count] = o
+		}
+		wi.Bids = append(wi.Bids, &BidOutcome{
+			Chips:  nationalCounts(cs),
+			Count:  count,
+			Wins:   o.Wins,
+			Ties:   o.Ties,
+			Losses: o.Losses,
+			Best:   o.Best,
+			Worst:  o.Worst,
+		})
+	}
+	return wi
+}
+
+// legalBidsFor provides every combination of chips player p may play in ward w.
+func (g *Game) legalBidsFor(p *Player, w *Ward) []Chips {
+	bids := []Chips{{}}
+	for _, n := range g.Nationalities() {
+		if w.Immigrants[n] <= 0 {
+			continue
+		}
+
+		var next []Chips
+		for _, cs := range bids {
+			for count := 0; count <= p.ChipsFor(n); count++ {
+				cs2 := make(Chips, len(cs)+1)
+				for n2, count2 := range cs {
+					cs2[n2] = count2
+				}
+				cs2[n] = count
+				next = append(next, cs2)
+			}
+		}
+		bids = next
+	}
+	return bids
+}
+
+// outcomesFor tallies the outcomes of an election count against every combination of election counts of the opponents.
+// As in resolve, the candidate having the highest count wins, and no candidate wins a tie for the highest count.
+func (wi *WhatIf) outcomesFor(count int) *BidOutcome {
+	o := new(BidOutcome)
+	var tally func(int, int)
+	tally = func(i, max int) {
+		if i == len(wi.Opponents) {
+			switch outcome(count, max) {
+			case outcomeWin:
+				o.Wins++
+			case outcomeTie:
+				o.Ties++
+			default:
+				o.Losses++
+			}
+			return
+		}
+
+		op := wi.Opponents[i]
+		for c := op.Bosses; c <= op.MaxInfluence; c++ {
+			if c > max {
+				tally(i+1, c)
+			} else {
+				tally(i+1, max)
+			}
+		}
+	}
+	tally(0, 0)
+
+	var best, worst int
+	for _, op := range wi.Opponents {
+		if op.Bosses > best {
+			best = op.Bosses
+		}
+		if op.MaxInfluence > worst {
+			worst = op.MaxInfluence
+		}
+	}
+	o.Best, o.Worst = outcome(count, best), outcome(count, worst)
+	return o
+}
+
+func outcome(count, max int) string {
+	switch {
+	case count > max:
+		return outcomeWin
+	case count == max:
+		return outcomeTie
+	default:
+		return outcomeLose
+	}
+}
+
+func (g *Game) validateWhatIf(cu *user.User) (*Player, error) {
+	cp := g.CurrentPlayerFor(cu)
+	switch {
+	case !g.InElectionsPhase() || g.CurrentWard() == nil:
+		return nil, g.verror(ErrWrongPhase, "There is no election in progress.")
+	case cp == nil:
+		return nil, g.verror(ErrNotCurrentPlayer, "Only the current player can consider a bid.")
+	case !cp.Candidate:
+		return nil, g.verror(ErrNotCandidate, "You are not a candidate in ward %d.", g.CurrentWardID)
+	}
+	return cp, nil
+}
+
+func (client *Client) whatIf(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client.Log.Debugf(msgEnter)
+		defer client.Log.Debugf(msgExit)
+
+		g := gameFrom(c)
+		if g == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": ErrGameNotFound.Error()})
+			return
+		}
+
+		cu, err := client.User.Current(c)
+		if err != nil {
+			client.Log.Debugf(err.Error())
+		}
+
+		cp, err := g.validateWhatIf(cu)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, g.WhatIf(cp))
+	}
+}